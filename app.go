@@ -5,44 +5,31 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
+	"github.com/HemendCo/go-core/config"
+	"github.com/HemendCo/go-core/health"
 	"github.com/HemendCo/go-core/helpers"
 	"github.com/HemendCo/go-core/plugins"
 )
 
-type Keywords string
-
-const (
-	CacheKeyword         Keywords = "cache"
-	LoggerKeyword        Keywords = "logger"
-	CLIKeyword           Keywords = "cli"
-	DatabaseKeyword      Keywords = "database"
-	WorkerKeyword        Keywords = "worker"
-	SMSKeyword           Keywords = "sms"
-	PluginManagerKeyword Keywords = "pluginManager"
-)
-
-func (k Keywords) IsValid() bool {
-	switch k {
-	case CacheKeyword, LoggerKeyword, CLIKeyword, DatabaseKeyword, WorkerKeyword, SMSKeyword, PluginManagerKeyword:
-		return true
-	}
-	return false
-}
-
 type Option interface {
 }
 
 // Option specifies the task processing behavior.
 type option struct {
-	rootPath string
-	config   interface{}
+	rootPath        string
+	config          interface{}
+	shutdownTimeout time.Duration
+	retryPolicy     RetryPolicy
 }
 
 // Internal option representations.
 type (
-	rootPathOption string
-	configOption   interface{}
+	rootPathOption        string
+	configOption          struct{ config interface{} }
+	shutdownTimeoutOption time.Duration
+	retryOption           RetryPolicy
 )
 
 // Queue returns an option to specify the queue to enqueue the task into.
@@ -51,13 +38,26 @@ func RootPath(name string) Option {
 }
 
 func Config(config interface{}) Option {
-	return configOption(config)
+	return configOption{config: config}
+}
+
+// ShutdownTimeout returns an option to specify how long Run waits for services to stop.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return shutdownTimeoutOption(timeout)
+}
+
+// Retry returns an option to specify how failed service constructions are retried.
+func Retry(policy RetryPolicy) Option {
+	return retryOption(policy)
 }
 
 type App struct {
-	context   *context.Context
+	context   context.Context
+	cancel    context.CancelFunc
 	opt       option
-	singleton Singleton
+	singleton *Singleton
+	lifecycle lifecycle
+	health    *health.Registry
 }
 
 // Global variable to hold the singleton instance
@@ -74,8 +74,10 @@ func defaultOptions() []Option {
 
 func composeOptions(opts ...Option) (option, error) {
 	res := option{
-		rootPath: "/",
-		config:   nil,
+		rootPath:        "/",
+		config:          nil,
+		shutdownTimeout: 30 * time.Second,
+		retryPolicy:     DefaultRetryPolicy(),
 	}
 	for _, opt := range opts {
 		switch opt := opt.(type) {
@@ -83,8 +85,11 @@ func composeOptions(opts ...Option) (option, error) {
 			rootPath := string(opt)
 			res.rootPath = rootPath
 		case configOption:
-			config := interface{}(opt)
-			res.config = config
+			res.config = opt.config
+		case shutdownTimeoutOption:
+			res.shutdownTimeout = time.Duration(opt)
+		case retryOption:
+			res.retryPolicy = RetryPolicy(opt)
 		default:
 			// ignore unexpected option
 		}
@@ -92,25 +97,52 @@ func composeOptions(opts ...Option) (option, error) {
 	return res, nil
 }
 
+// NewApp creates an independent application with its own services and root context.
+// Use it for tests or to host several applications in one process.
+func NewApp(opts ...Option) *App {
+	opts = append(defaultOptions(), opts...)
+	opt, err := composeOptions(opts...)
+	if err != nil {
+		// options is not valid
+		log.Fatalf("Error composing options: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	app := &App{
+		context:   ctx,
+		cancel:    cancel,
+		opt:       opt,
+		singleton: NewSingleton(),
+		health:    health.NewRegistry(),
+	}
+	app.singleton.SetRetryPolicy(opt.retryPolicy)
+	app.health.Discover(app.serviceCheckers)
+
+	return app
+}
+
 // Function to get the app instance (similar to app() in Laravel)
+// The global app is created on the first call; options passed to later calls are ignored.
 func CreateApp(opts ...Option) *App {
+	created := false
 	onceApp.Do(func() {
-		opts = append(defaultOptions(), opts...)
-		opt, err := composeOptions(opts...)
-		if err != nil {
-			// options is not valid
-			log.Fatalf("Error composing options: %v", err)
-		}
-
-		appInstance = &App{
-			opt:       opt,
-			singleton: *NewSingleton(),
-		}
+		appInstance = NewApp(opts...)
+		created = true
 	})
+
+	if !created && len(opts) > 0 {
+		log.Printf("[App] CreateApp: the global app already exists, %d option(s) ignored; use NewApp for a separate app", len(opts))
+	}
+
 	return appInstance
 }
 
-func (a *App) GetContext() *context.Context {
+// GetContext returns the root context of the application, which is cancelled on shutdown.
+func (a *App) GetContext() context.Context {
+	if a.context == nil {
+		return context.Background()
+	}
 	return a.context
 }
 
@@ -123,6 +155,40 @@ func (a *App) SetConfig(config interface{}) *App {
 	return a
 }
 
+// LoadConfig loads the configuration files from the root path of the application,
+// merged with the environment variables, and makes it the configuration of the application.
+// Options override the defaults, e.g. config.Profile("prod") or config.Path("configs").
+func (a *App) LoadConfig(opts ...config.Option) (*config.Config, error) {
+	opts = append([]config.Option{config.Path(a.RootPath())}, opts...)
+
+	cfg := config.New(opts...)
+	if err := cfg.Load(); err != nil {
+		return nil, err
+	}
+
+	a.SetConfig(cfg)
+	return cfg, nil
+}
+
+// Configuration returns the configuration loaded by LoadConfig, or nil if none was loaded.
+func (a *App) Configuration() *config.Config {
+	cfg, _ := a.opt.config.(*config.Config)
+	return cfg
+}
+
+// WatchConfig reloads the configuration when its files or environment variables change,
+// polling every interval until the application shuts down. Subscribers of the changed
+// sections are notified, see config.Config.Subscribe.
+func (a *App) WatchConfig(interval time.Duration) error {
+	cfg := a.Configuration()
+	if cfg == nil {
+		return fmt.Errorf("[App] no configuration loaded to watch")
+	}
+
+	go cfg.Watch(a.GetContext(), interval)
+	return nil
+}
+
 func (a *App) RootPath() string {
 	return a.opt.rootPath
 }
@@ -158,14 +224,106 @@ func (a *App) RegisterPlugin(path string, config interface{}) error {
 	return fmt.Errorf("unsupported plugin: %s", path)
 }
 
-func (a *App) Use(key Keywords, createFunc func() (interface{}, error)) error {
-	return a.singleton.Register(string(key), createFunc)
+// Use registers a creator function for the key. Service options attach lifecycle hooks to the service.
+func (a *App) Use(key Keywords, createFunc func() (interface{}, error), opts ...ServiceOption) error {
+	if !key.IsValid() {
+		return fmt.Errorf("[App] invalid service key %q", key)
+	}
+
+	if err := a.singleton.Register(string(key), a.lifecycle.track(key, createFunc)); err != nil {
+		return err
+	}
+
+	a.lifecycle.register(key, composeServiceOptions(SingletonLifetime, opts...))
+	return nil
+}
+
+// UseTransient registers a creator function that is executed every time the key is resolved.
+// Instances resolved from a scope are stopped when the scope ends; otherwise the caller owns them.
+func (a *App) UseTransient(key Keywords, createFunc func() (interface{}, error), opts ...ServiceOption) error {
+	if !key.IsValid() {
+		return fmt.Errorf("[App] invalid service key %q", key)
+	}
+
+	if err := a.singleton.RegisterTransient(string(key), createFunc); err != nil {
+		return err
+	}
+
+	a.lifecycle.register(key, composeServiceOptions(TransientLifetime, opts...))
+	return nil
+}
+
+// UseScoped registers a creator function that is executed once per scope, see NewScope.
+// Scoped instances are stopped when their scope ends.
+func (a *App) UseScoped(key Keywords, createFunc func(scope *Scope) (interface{}, error), opts ...ServiceOption) error {
+	if !key.IsValid() {
+		return fmt.Errorf("[App] invalid service key %q", key)
+	}
+
+	if err := a.singleton.RegisterScoped(string(key), createFunc); err != nil {
+		return err
+	}
+
+	a.lifecycle.register(key, composeServiceOptions(ScopedLifetime, opts...))
+	return nil
 }
 
 func (a *App) Get(key Keywords) (interface{}, error) {
 	return a.singleton.Get(string(key))
 }
 
+// Instance returns the created instance of a service without creating it.
+func (a *App) Instance(key Keywords) (interface{}, bool) {
+	return a.singleton.Instance(string(key))
+}
+
 func (a *App) Exists(key Keywords) bool {
 	return a.singleton.Exists(string(key))
 }
+
+// Reset drops the created instance of a service, so the next Get creates it again.
+// The dropped instance is stopped like on shutdown.
+func (a *App) Reset(ctx context.Context, key Keywords) error {
+	instance, created := a.singleton.Instance(string(key))
+	if created {
+		a.lifecycle.forget(key)
+	}
+
+	if err := a.singleton.Reset(string(key)); err != nil {
+		return err
+	}
+
+	if created {
+		return a.runInstanceHooks(ctx, key, instance, true)
+	}
+	return nil
+}
+
+// Replace hot-swaps the creator function of a service. If the application is started,
+// the new instance is created and started before the previous instance is stopped.
+func (a *App) Replace(ctx context.Context, key Keywords, createFunc func() (interface{}, error)) error {
+	instance, created := a.singleton.Instance(string(key))
+	if created {
+		a.lifecycle.forget(key)
+	}
+
+	lifetime, _ := a.singleton.Lifetime(string(key))
+	if lifetime == SingletonLifetime {
+		createFunc = a.lifecycle.track(key, createFunc)
+	}
+
+	if err := a.singleton.Replace(string(key), createFunc); err != nil {
+		return err
+	}
+
+	if lifetime == SingletonLifetime && a.lifecycle.isStarted() {
+		if err := a.runHooks(ctx, key, false); err != nil {
+			return fmt.Errorf("[App] failed to start %s: %w", key, err)
+		}
+	}
+
+	if created {
+		return a.runInstanceHooks(ctx, key, instance, true)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestComposeOptions(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second}
+	cfg := map[string]string{"name": "app"}
+
+	opt, err := composeOptions(RootPath("/srv"), Config(cfg), ShutdownTimeout(5*time.Second), Retry(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opt.rootPath != "/srv" {
+		t.Errorf("rootPath = %q, want /srv", opt.rootPath)
+	}
+	if got, ok := opt.config.(map[string]string); !ok || got["name"] != "app" {
+		t.Errorf("config = %#v, want %#v", opt.config, cfg)
+	}
+	if opt.shutdownTimeout != 5*time.Second {
+		t.Errorf("shutdownTimeout = %v, want 5s", opt.shutdownTimeout)
+	}
+	if opt.retryPolicy != policy {
+		t.Errorf("retryPolicy = %+v, want %+v", opt.retryPolicy, policy)
+	}
+}
+
+func TestRootContext(t *testing.T) {
+	if ctx := (&App{}).GetContext(); ctx == nil || ctx.Err() != nil {
+		t.Fatalf("GetContext of an App without root context = %v, want a live context", ctx)
+	}
+
+	app := NewApp()
+	ctx := app.GetContext()
+	if ctx == nil || ctx.Err() != nil {
+		t.Fatalf("GetContext after NewApp = %v, want a live context", ctx)
+	}
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("root context cancelled by Start: %v", err)
+	}
+
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("root context not cancelled by Shutdown")
+	}
+	if app.GetContext() != ctx {
+		t.Fatal("GetContext returned another context after Shutdown")
+	}
+}
+
+func TestNewAppsAreIndependent(t *testing.T) {
+	ctx := context.Background()
+	first := NewApp(Config("first"), ShutdownTimeout(time.Second))
+	second := NewApp(Config("second"))
+
+	var stops recorder
+	for _, app := range []*App{first, second} {
+		name := app.Config().(string)
+		if err := app.Use("service", func() (interface{}, error) { return name, nil }, OnStop(stops.stop(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := first.Use("only-first", func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+	first.SetConfig("changed")
+
+	if err := first.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "first service", got: MustResolve[string](first, "service"), want: "first"},
+		{name: "second service", got: MustResolve[string](second, "service"), want: "second"},
+		{name: "registry", got: second.Exists("only-first"), want: false},
+		{name: "config", got: second.Config(), want: "second"},
+		{name: "shutdown timeout", got: second.opt.shutdownTimeout, want: 30 * time.Second},
+		{name: "stopped services", got: stops.names(), want: "first"},
+		{name: "first root context", got: first.GetContext().Err(), want: context.Canceled},
+		{name: "second root context", got: second.GetContext().Err(), want: nil},
+		{name: "second lifecycle", got: second.lifecycle.isStarted(), want: false},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// The second application still starts after the first one was shut down
+	if err := second.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateAppIsSeparateFromNewApp(t *testing.T) {
+	global := CreateApp()
+	if CreateApp(Config("ignored")) != global {
+		t.Fatal("CreateApp returned another application")
+	}
+	if app := NewApp(); app == global {
+		t.Fatal("NewApp returned the global application")
+	}
+}
+
+func TestNewAppsInParallel(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+
+			app := NewApp()
+			if err := app.Use("service", func() (interface{}, error) { return i, nil }); err != nil {
+				t.Fatal(err)
+			}
+			if err := app.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := MustResolve[int](app, "service"); got != i {
+				t.Fatalf("resolved %d, want %d", got, i)
+			}
+			if err := app.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
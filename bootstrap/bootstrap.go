@@ -0,0 +1,644 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/cache"
+	"github.com/HemendCo/go-core/cache/cache_models"
+	"github.com/HemendCo/go-core/config"
+	"github.com/HemendCo/go-core/database"
+	"github.com/HemendCo/go-core/database/db_config"
+	"github.com/HemendCo/go-core/database/db_interfaces"
+	"github.com/HemendCo/go-core/events"
+	"github.com/HemendCo/go-core/health"
+	corehttp "github.com/HemendCo/go-core/http"
+	"github.com/HemendCo/go-core/http/http_models"
+	"github.com/HemendCo/go-core/locks"
+	"github.com/HemendCo/go-core/locks/locks_models"
+	"github.com/HemendCo/go-core/logger"
+	"github.com/HemendCo/go-core/logger/logger_models"
+	"github.com/HemendCo/go-core/mail"
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/HemendCo/go-core/notifications"
+	"github.com/HemendCo/go-core/scheduler"
+	"github.com/HemendCo/go-core/scheduler/scheduler_models"
+	"github.com/HemendCo/go-core/secrets"
+	"github.com/HemendCo/go-core/secrets/secrets_models"
+	"github.com/HemendCo/go-core/sms"
+	"github.com/HemendCo/go-core/sms/sms_models"
+	"github.com/HemendCo/go-core/worker"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+	"github.com/HemendCo/go-core/worker/worker_models"
+)
+
+// Bootstrapper registers the subsystems of an application from its configuration.
+//
+// Each subsystem reads the section named after its keyword. The "driver" key selects
+// the driver and the sub-section named after the driver holds the driver configuration:
+//
+//	cache:
+//	  driver: redis
+//	  redis:
+//	    host: localhost
+//	database:
+//	  connections:
+//	    main:
+//	      driver: mysql
+//	worker:
+//	  driver: file
+//	  file:
+//	    path: storage/tasks
+//	locks:
+//	  driver: redis
+//	  redis:
+//	    host: localhost
+//	mail:
+//	  driver: smtp
+//	  smtp:
+//	    host: smtp.example.com
+//	    from: noreply@example.com
+//	scheduler:
+//	  timezone: Asia/Tehran
+//	  leader_ttl: 30s
+//	http:
+//	  driver: nethttp
+//	  health: true # serve /healthz and /readyz
+//	  nethttp:
+//	    port: 8080
+//
+// Values of the form "secret://<path>" are resolved by the driver of the secrets section
+// before the other subsystems are registered:
+//
+//	secrets:
+//	  driver: vault
+//	  vault:
+//	    path: storage/secrets.vault
+//	database:
+//	  connections:
+//	    main:
+//	      password: secret://db/main/password
+type Bootstrapper struct {
+	app           *core.App
+	overrides     map[core.Keywords]func() (interface{}, error)
+	skipped       map[core.Keywords]bool
+	driverConfigs map[core.Keywords]map[string]func() interface{}
+
+	cacheDrivers    []cache.CacheDriver
+	databaseDrivers []db_interfaces.DatabaseDriver
+	workerDrivers   []worker_interfaces.WorkerDriver
+	loggerDrivers   []logger.LoggerDriver
+	smsDrivers      []sms.SMSDriver
+	secretsDrivers  []secrets.SecretsDriver
+	httpDrivers     []corehttp.HTTPDriver
+	mailDrivers     []mail.MailDriver
+	locksDrivers    []locks.LockDriver
+}
+
+func NewBootstrapper(app *core.App) *Bootstrapper {
+	b := &Bootstrapper{
+		app:           app,
+		overrides:     make(map[core.Keywords]func() (interface{}, error)),
+		skipped:       make(map[core.Keywords]bool),
+		driverConfigs: make(map[core.Keywords]map[string]func() interface{}),
+	}
+
+	// register default driver configurations
+	b.DriverConfig(core.CacheKeyword, "file", func() interface{} { return &cache_models.FileCacheConfig{} })
+	b.DriverConfig(core.CacheKeyword, "map", func() interface{} { return &cache_models.MapCacheConfig{} })
+	b.DriverConfig(core.CacheKeyword, "redis", func() interface{} { return &cache_models.RedisCacheConfig{} })
+	b.DriverConfig(core.WorkerKeyword, "file", func() interface{} { return &worker_models.FileWorkerConfig{} })
+	b.DriverConfig(core.WorkerKeyword, "redis", func() interface{} { return &worker_models.RedisWorkerConfig{} })
+	b.DriverConfig(core.LoggerKeyword, "file", func() interface{} { return &logger_models.FileLoggerConfig{} })
+	b.DriverConfig(core.SMSKeyword, "hemend", func() interface{} { return &sms_models.HemendSMSConfig{} })
+	b.DriverConfig(core.MailKeyword, "smtp", func() interface{} { return &mail_models.SMTPMailConfig{} })
+	b.DriverConfig(core.MailKeyword, "log", func() interface{} { return &mail_models.LogMailConfig{} })
+	b.DriverConfig(core.HTTPKeyword, "nethttp", func() interface{} { return &http_models.NetHTTPConfig{} })
+	b.DriverConfig(core.LocksKeyword, "redis", func() interface{} { return &locks_models.RedisLockConfig{} })
+	b.DriverConfig(core.LocksKeyword, "file", func() interface{} { return &locks_models.FileLockConfig{} })
+	b.DriverConfig(core.LocksKeyword, "map", func() interface{} { return &locks_models.MapLockConfig{} })
+	b.DriverConfig(core.SecretsKeyword, "env", func() interface{} { return &secrets_models.EnvSecretsConfig{} })
+	b.DriverConfig(core.SecretsKeyword, "file", func() interface{} { return &secrets_models.FileSecretsConfig{} })
+	b.DriverConfig(core.SecretsKeyword, "vault", func() interface{} { return &secrets_models.VaultSecretsConfig{} })
+
+	return b
+}
+
+// DriverConfig registers the configuration type of a driver; newConfig returns a pointer
+// to the struct the driver sub-section is bound into. Use it for custom drivers.
+func (b *Bootstrapper) DriverConfig(key core.Keywords, driverName string, newConfig func() interface{}) *Bootstrapper {
+	if b.driverConfigs[key] == nil {
+		b.driverConfigs[key] = make(map[string]func() interface{})
+	}
+	b.driverConfigs[key][driverName] = newConfig
+	return b
+}
+
+// Override registers the service for the key with createFunc instead of the configuration.
+func (b *Bootstrapper) Override(key core.Keywords, createFunc func() (interface{}, error)) *Bootstrapper {
+	b.overrides[key] = createFunc
+	return b
+}
+
+// Skip leaves the keys unregistered, e.g. to register them by hand later.
+func (b *Bootstrapper) Skip(keys ...core.Keywords) *Bootstrapper {
+	for _, key := range keys {
+		b.skipped[key] = true
+	}
+	return b
+}
+
+// CacheDrivers registers additional cache drivers.
+func (b *Bootstrapper) CacheDrivers(drivers ...cache.CacheDriver) *Bootstrapper {
+	b.cacheDrivers = append(b.cacheDrivers, drivers...)
+	return b
+}
+
+// DatabaseDrivers registers additional database drivers.
+func (b *Bootstrapper) DatabaseDrivers(drivers ...db_interfaces.DatabaseDriver) *Bootstrapper {
+	b.databaseDrivers = append(b.databaseDrivers, drivers...)
+	return b
+}
+
+// WorkerDrivers registers additional worker drivers.
+func (b *Bootstrapper) WorkerDrivers(drivers ...worker_interfaces.WorkerDriver) *Bootstrapper {
+	b.workerDrivers = append(b.workerDrivers, drivers...)
+	return b
+}
+
+// LoggerDrivers registers additional logger drivers.
+func (b *Bootstrapper) LoggerDrivers(drivers ...logger.LoggerDriver) *Bootstrapper {
+	b.loggerDrivers = append(b.loggerDrivers, drivers...)
+	return b
+}
+
+// SMSDrivers registers additional sms drivers.
+func (b *Bootstrapper) SMSDrivers(drivers ...sms.SMSDriver) *Bootstrapper {
+	b.smsDrivers = append(b.smsDrivers, drivers...)
+	return b
+}
+
+// SecretsDrivers registers additional secrets drivers.
+func (b *Bootstrapper) SecretsDrivers(drivers ...secrets.SecretsDriver) *Bootstrapper {
+	b.secretsDrivers = append(b.secretsDrivers, drivers...)
+	return b
+}
+
+// MailDrivers registers additional mail drivers.
+func (b *Bootstrapper) MailDrivers(drivers ...mail.MailDriver) *Bootstrapper {
+	b.mailDrivers = append(b.mailDrivers, drivers...)
+	return b
+}
+
+// HTTPDrivers registers additional http drivers.
+func (b *Bootstrapper) HTTPDrivers(drivers ...corehttp.HTTPDriver) *Bootstrapper {
+	b.httpDrivers = append(b.httpDrivers, drivers...)
+	return b
+}
+
+// LocksDrivers registers additional lock drivers.
+func (b *Bootstrapper) LocksDrivers(drivers ...locks.LockDriver) *Bootstrapper {
+	b.locksDrivers = append(b.locksDrivers, drivers...)
+	return b
+}
+
+// Boot registers a service for every configured subsystem, the event dispatcher, the notifier
+// and every override.
+// The configuration is loaded from the root path of the application if it was not loaded yet.
+// Services are created lazily, on first use or when the application starts.
+// When the configuration is reloaded, see core.App.WatchConfig, the services of the
+// changed sections are re-initialized, except the http server which needs a restart.
+func (b *Bootstrapper) Boot() error {
+	cfg := b.app.Configuration()
+	if cfg == nil {
+		var err error
+		if cfg, err = b.app.LoadConfig(); err != nil {
+			return err
+		}
+	}
+
+	if err := b.bootSecrets(cfg); err != nil {
+		return err
+	}
+
+	// Services that need no configuration
+	builtins := []struct {
+		key        core.Keywords
+		createFunc func() (interface{}, error)
+	}{
+		{core.EventsKeyword, func() (interface{}, error) { return events.NewDispatcher(b.app), nil }},
+		{core.NotificationsKeyword, func() (interface{}, error) { return notifications.NewNotifier(b.app), nil }},
+	}
+
+	services := []struct {
+		key        core.Keywords
+		createFunc func(cfg *config.Config) (interface{}, error)
+		opts       []core.ServiceOption
+		reloadable bool
+	}{
+		{core.LoggerKeyword, b.createLogger, nil, true},
+		{core.CacheKeyword, b.createCache, nil, true},
+		{core.DatabaseKeyword, b.createDatabase, nil, true},
+		{core.WorkerKeyword, b.createWorker, nil, true},
+		{core.LocksKeyword, b.createLocks, nil, true},
+		{core.SMSKeyword, b.createSMS, nil, true},
+		{core.MailKeyword, b.createMail, nil, true},
+		{core.HTTPKeyword, b.createHTTP, []core.ServiceOption{core.OnStart(startHTTP)}, false},
+		{core.SchedulerKeyword, b.createScheduler, []core.ServiceOption{core.OnStart(startScheduler)}, false},
+	}
+
+	var registrations []registration
+	for _, service := range builtins {
+		if _, ok := b.overrides[service.key]; ok || b.skipped[service.key] || b.app.Exists(service.key) {
+			continue
+		}
+
+		registrations = append(registrations, registration{key: service.key, createFunc: service.createFunc})
+	}
+
+	// Overrides keep the lifecycle hooks of the service they replace
+	serviceOpts := make(map[core.Keywords][]core.ServiceOption)
+	for _, service := range services {
+		serviceOpts[service.key] = service.opts
+
+		if b.skipped[service.key] || b.app.Exists(service.key) {
+			continue
+		}
+
+		if _, ok := b.overrides[service.key]; ok || !cfg.Has(string(service.key)) {
+			continue
+		}
+
+		createFunc := service.createFunc
+		reg := registration{
+			key: service.key,
+			createFunc: func() (interface{}, error) {
+				return createFunc(cfg)
+			},
+			opts: service.opts,
+		}
+		if service.reloadable {
+			reg.reload = createFunc
+		}
+		registrations = append(registrations, reg)
+	}
+
+	for key, createFunc := range b.overrides {
+		if b.skipped[key] || key == core.SecretsKeyword {
+			continue
+		}
+
+		registrations = append(registrations, registration{key: key, createFunc: createFunc, opts: serviceOpts[key]})
+	}
+
+	// Dependencies are declared once every registered key is known
+	registered := make(map[core.Keywords]bool, len(registrations))
+	for _, reg := range registrations {
+		registered[reg.key] = true
+	}
+
+	for _, reg := range registrations {
+		opts := reg.opts
+		var dependencies []core.Keywords
+		for _, dependency := range serviceDependencies[reg.key] {
+			if registered[dependency] || b.app.Exists(dependency) {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		if len(dependencies) > 0 {
+			opts = append(append([]core.ServiceOption(nil), opts...), core.DependsOn(dependencies...))
+		}
+
+		if err := b.app.Use(reg.key, reg.createFunc, opts...); err != nil {
+			return err
+		}
+
+		if reg.reload != nil {
+			b.watch(cfg, reg.key, reg.reload)
+		}
+	}
+
+	return nil
+}
+
+// registration is a service registered by Boot
+type registration struct {
+	key        core.Keywords
+	createFunc func() (interface{}, error)
+	opts       []core.ServiceOption
+	reload     func(cfg *config.Config) (interface{}, error) // Recreates a reloadable service, nil otherwise
+}
+
+// serviceDependencies lists the services each built-in service resolves. They are declared
+// with core.DependsOn when registered, so they are validated and started first. The worker
+// resolves the services whose jobs it runs; services resolving the worker to enqueue jobs
+// do so on use only, which would otherwise be a cycle.
+var serviceDependencies = map[core.Keywords][]core.Keywords{
+	core.WorkerKeyword:        {core.EventsKeyword, core.NotificationsKeyword, core.MailKeyword},
+	core.SMSKeyword:           {core.CacheKeyword, core.LocksKeyword},
+	core.NotificationsKeyword: {core.SMSKeyword, core.MailKeyword, core.DatabaseKeyword},
+	core.SchedulerKeyword:     {core.LocksKeyword, core.WorkerKeyword},
+}
+
+// bootSecrets registers the secrets service, from its override or its configuration section,
+// and resolves the secret references of the configuration with it
+func (b *Bootstrapper) bootSecrets(cfg *config.Config) error {
+	key := core.SecretsKeyword
+	if b.skipped[key] {
+		return nil
+	}
+
+	if !b.app.Exists(key) {
+		createFunc, ok := b.overrides[key]
+		if !ok {
+			if !cfg.Has(string(key)) {
+				return nil
+			}
+			createFunc = func() (interface{}, error) {
+				return b.createSecrets(cfg)
+			}
+		}
+
+		if err := b.app.Use(key, createFunc); err != nil {
+			return err
+		}
+	}
+
+	instance, err := b.app.Get(key)
+	if err != nil {
+		return err
+	}
+
+	resolver, ok := instance.(config.SecretResolver)
+	if !ok {
+		return fmt.Errorf("%s service of type %T does not implement config.SecretResolver", key, instance)
+	}
+
+	return cfg.UseSecrets(resolver)
+}
+
+// reloadable is implemented by drivers that apply a new configuration in place
+type reloadable interface {
+	Name() string
+	Reload(config interface{}) error
+}
+
+// watch re-initializes the service of a key when its configuration section changes.
+// Drivers of the same name are reloaded in place, other services are replaced.
+func (b *Bootstrapper) watch(cfg *config.Config, key core.Keywords, createFunc func(cfg *config.Config) (interface{}, error)) {
+	cfg.Subscribe(string(key), func(event config.ChangeEvent) {
+		if event.New == nil {
+			log.Printf("[Bootstrapper] section %s was removed from the configuration, keeping the current %s", key, key)
+			return
+		}
+
+		instance, created := b.app.Instance(key)
+		if !created {
+			// Not created yet, the next Get uses the new configuration
+			return
+		}
+
+		if driver, ok := instance.(reloadable); ok && key != core.DatabaseKeyword {
+			driverName, driverConfig, err := b.driverConfig(cfg, key)
+			if err != nil {
+				log.Printf("[Bootstrapper] failed to reload %s: %v", key, err)
+				return
+			}
+
+			if driverName == driver.Name() {
+				if err := driver.Reload(driverConfig); err != nil {
+					log.Printf("[Bootstrapper] failed to reload %s: %v", key, err)
+				}
+				return
+			}
+		}
+
+		if err := b.app.Replace(b.app.GetContext(), key, func() (interface{}, error) {
+			return createFunc(cfg)
+		}); err != nil {
+			log.Printf("[Bootstrapper] failed to replace %s: %v", key, err)
+		}
+	})
+}
+
+// driverConfig returns the selected driver of a section and its bound configuration
+func (b *Bootstrapper) driverConfig(cfg *config.Config, key core.Keywords) (string, interface{}, error) {
+	driverName := cfg.GetString(string(key) + ".driver")
+	if driverName == "" {
+		return "", nil, fmt.Errorf("missing %s.driver in configuration", key)
+	}
+
+	newConfig, ok := b.driverConfigs[key][driverName]
+	if !ok {
+		return "", nil, fmt.Errorf("no configuration type registered for %s driver %s", key, driverName)
+	}
+
+	target := newConfig()
+	if err := cfg.Bind(string(key)+"."+driverName, target); err != nil {
+		return "", nil, err
+	}
+
+	// Drivers expect the configuration struct by value
+	return driverName, reflect.ValueOf(target).Elem().Interface(), nil
+}
+
+func (b *Bootstrapper) createLogger(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.LoggerKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	return logger.NewLoggerManager(b.loggerDrivers...).CreateLoggerFactory(driverName, driverConfig)
+}
+
+func (b *Bootstrapper) createCache(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.CacheKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	return cache.NewCacheManager(b.cacheDrivers...).
+		WithContext(b.app.GetContext()).
+		CreateCacheFactory(driverName, driverConfig)
+}
+
+func (b *Bootstrapper) createDatabase(cfg *config.Config) (interface{}, error) {
+	configs, err := config.BindAs[map[string]db_config.DBConfig](cfg, string(core.DatabaseKeyword)+".connections")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no connections configured in %s.connections", core.DatabaseKeyword)
+	}
+
+	// A single connection is the default connection
+	if len(configs) == 1 {
+		for name, dbConfig := range configs {
+			dbConfig.IsDefaultConnection = true
+			configs[name] = dbConfig
+		}
+	}
+
+	hasDefault := false
+	for _, dbConfig := range configs {
+		hasDefault = hasDefault || dbConfig.IsDefaultConnection
+	}
+	if !hasDefault {
+		return nil, fmt.Errorf("no default connection configured in %s.connections", core.DatabaseKeyword)
+	}
+
+	dbm := database.NewDatabaseManager(b.databaseDrivers...).WithContext(b.app.GetContext())
+
+	connections := make(map[string]db_interfaces.DatabaseConnection)
+	for name, dbConfig := range configs {
+		conn, err := dbm.CreateDatabaseFactory(name, dbConfig)
+		if err != nil {
+			for _, opened := range connections {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("failed to create connection '%s': %w", name, err)
+		}
+		connections[name] = conn
+	}
+
+	return database.NewDB(dbm, connections), nil
+}
+
+func (b *Bootstrapper) createWorker(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.WorkerKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := worker.NewWorkerManager(b.app, b.workerDrivers...).CreateWorkerFactory(driverName, driverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run the queued event listeners, notifications and messages
+	if dispatcher, err := core.Resolve[*events.Dispatcher](b.app, core.EventsKeyword); err == nil {
+		driver.RegisterJobHandlers(dispatcher.Job())
+	}
+	if notifier, err := core.Resolve[*notifications.Notifier](b.app, core.NotificationsKeyword); err == nil {
+		driver.RegisterJobHandlers(notifier.Job())
+	}
+	if b.app.Exists(core.MailKeyword) {
+		driver.RegisterJobHandlers(&mail.Job{})
+	}
+
+	return driver, nil
+}
+
+func (b *Bootstrapper) createSMS(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.SMSKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := sms.NewSMSManager(b.app)
+	for _, driver := range b.smsDrivers {
+		manager.RegisterDriver(driver)
+	}
+
+	return manager.CreateSMSFactory(driverName, driverConfig)
+}
+
+func (b *Bootstrapper) createLocks(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.LocksKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := locks.NewLocksManager(b.locksDrivers...).CreateLocksFactory(driverName, driverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return locks.NewLocker(driver), nil
+}
+
+func (b *Bootstrapper) createSecrets(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.SecretsKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets.NewSecretsManager(b.secretsDrivers...).CreateSecretsFactory(driverName, driverConfig)
+}
+
+func (b *Bootstrapper) createMail(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.MailKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := mail.NewMailManager(b.app)
+	for _, driver := range b.mailDrivers {
+		manager.RegisterDriver(driver)
+	}
+
+	return manager.CreateMailFactory(driverName, driverConfig)
+}
+
+func (b *Bootstrapper) createHTTP(cfg *config.Config) (interface{}, error) {
+	driverName, driverConfig, err := b.driverConfig(cfg, core.HTTPKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := corehttp.NewHTTPManager(b.app, b.httpDrivers...).CreateHTTPFactory(driverName, driverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	server := corehttp.NewServer(b.app, driver)
+	if cfg.GetBool(string(core.HTTPKeyword) + ".health") {
+		server.Router().Mount(http.MethodGet, "/healthz", health.LivenessHandler(b.app.Health()))
+		server.Router().Mount(http.MethodGet, "/readyz", health.ReadinessHandler(b.app.Health()))
+	}
+
+	return server, nil
+}
+
+// createScheduler creates the scheduler from the scheduler section
+func (b *Bootstrapper) createScheduler(cfg *config.Config) (interface{}, error) {
+	var schedulerConfig scheduler_models.SchedulerConfig
+	if err := cfg.Bind(string(core.SchedulerKeyword), &schedulerConfig); err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(schedulerConfig.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduler.NewScheduler(b.app,
+		scheduler.Location(location),
+		scheduler.LeaderKey(schedulerConfig.LeaderKey),
+		scheduler.LeaderTTL(schedulerConfig.LeaderTTL),
+	)
+}
+
+// startScheduler runs the scheduled jobs once the application is started
+func startScheduler(ctx context.Context, instance interface{}) error {
+	s, ok := instance.(*scheduler.Scheduler)
+	if !ok {
+		return fmt.Errorf("%s service of type %T is not a *scheduler.Scheduler", core.SchedulerKeyword, instance)
+	}
+	return s.Start(ctx)
+}
+
+// startHTTP starts serving once the application is started
+func startHTTP(ctx context.Context, instance interface{}) error {
+	server, ok := instance.(*corehttp.Server)
+	if !ok {
+		return fmt.Errorf("%s service of type %T is not a *http.Server", core.HTTPKeyword, instance)
+	}
+	return server.Start(ctx)
+}
@@ -0,0 +1,154 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/config"
+	"github.com/HemendCo/go-core/mail"
+	"github.com/HemendCo/go-core/scheduler"
+)
+
+// newApp creates an application whose configuration holds the values
+func newApp(t *testing.T, values map[string]interface{}) *core.App {
+	t.Helper()
+
+	cfg := config.New(config.Path(t.TempDir()), config.EnvPrefix(""), config.Defaults(values))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	return core.NewApp(core.Config(cfg))
+}
+
+func TestBootDeclaresDependencies(t *testing.T) {
+	app := newApp(t, map[string]interface{}{
+		"cache.driver":     "map",
+		"locks.driver":     "map",
+		"worker.driver":    "file",
+		"worker.file.path": t.TempDir(),
+		"mail.driver":      "log",
+		"sms.driver":       "hemend",
+		"scheduler":        map[string]interface{}{"leader_ttl": "30s"},
+	})
+
+	if err := NewBootstrapper(app).Boot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	edges := make(map[core.GraphEdge]bool)
+	for _, edge := range app.DependencyGraph().Edges {
+		edges[edge] = true
+	}
+
+	tests := []struct {
+		from, to core.Keywords
+		want     bool
+	}{
+		{core.WorkerKeyword, core.EventsKeyword, true},
+		{core.WorkerKeyword, core.NotificationsKeyword, true},
+		{core.WorkerKeyword, core.MailKeyword, true},
+		{core.SMSKeyword, core.CacheKeyword, true},
+		{core.SMSKeyword, core.LocksKeyword, true},
+		{core.NotificationsKeyword, core.SMSKeyword, true},
+		{core.NotificationsKeyword, core.MailKeyword, true},
+		{core.SchedulerKeyword, core.LocksKeyword, true},
+		{core.SchedulerKeyword, core.WorkerKeyword, true},
+		// Services that are not configured are not declared
+		{core.NotificationsKeyword, core.DatabaseKeyword, false},
+	}
+	for _, tt := range tests {
+		if got := edges[core.GraphEdge{From: tt.from, To: tt.to}]; got != tt.want {
+			t.Errorf("edge %s -> %s declared = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestBootOverrideKeepsServiceOptions(t *testing.T) {
+	app := newApp(t, map[string]interface{}{
+		"locks.driver": "map",
+	})
+
+	var overridden *scheduler.Scheduler
+	b := NewBootstrapper(app).Override(core.SchedulerKeyword, func() (interface{}, error) {
+		var err error
+		overridden, err = scheduler.NewScheduler(app)
+		return overridden, err
+	})
+	if err := b.Boot(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown(ctx)
+
+	// The start hook of the scheduler ran, so starting it again fails
+	if err := overridden.Start(ctx); err == nil {
+		t.Fatal("the overridden scheduler was not started by the application")
+	}
+
+	found := false
+	for _, edge := range app.DependencyGraph().Edges {
+		found = found || edge == core.GraphEdge{From: core.SchedulerKeyword, To: core.LocksKeyword}
+	}
+	if !found {
+		t.Fatal("the override does not declare its dependency on the locks service")
+	}
+}
+
+func TestCreateScheduler(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		location string // Location the entries run in
+		wantErr  bool
+	}{
+		{name: "default timezone", location: "UTC"},
+		{name: "timezone", timezone: "Asia/Tehran", location: "Asia/Tehran"},
+		{name: "unknown timezone", timezone: "Nowhere/City", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := time.LoadLocation(tt.location)
+			if err != nil {
+				t.Skip(err)
+			}
+
+			app := newApp(t, map[string]interface{}{
+				"scheduler": map[string]interface{}{"timezone": tt.timezone, "leader_ttl": "10s"},
+			})
+
+			instance, err := NewBootstrapper(app).createScheduler(app.Configuration())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			s := instance.(*scheduler.Scheduler)
+			ctx := context.Background()
+			if err := startScheduler(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Shutdown(ctx)
+
+			entry, err := s.Cron("30 3 * * *", &mail.Job{}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next := entry.Next().In(location); next.Hour() != 3 || next.Minute() != 30 {
+				t.Fatalf("next run at %v, want 03:30 in %v", next, location)
+			}
+		})
+	}
+}
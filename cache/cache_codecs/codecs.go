@@ -0,0 +1,139 @@
+package cache_codecs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec encodes the values of a cache to the bytes stored by the drivers.
+type Codec interface {
+	Name() string
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, target interface{}) error
+}
+
+// New returns the named codec ("json", "gob", "msgpack" or "raw"), compressing encoded values
+// of at least threshold bytes with the named compression ("gzip" or "zstd"), if any.
+func New(name string, compression string, threshold int) (Codec, error) {
+	var codec Codec
+	switch name {
+	case "json":
+		codec = &JSONCodec{}
+	case "gob":
+		codec = &GobCodec{}
+	case "msgpack":
+		codec = &MsgpackCodec{}
+	case "raw":
+		codec = &RawCodec{}
+	default:
+		return nil, fmt.Errorf("unsupported cache codec %s", name)
+	}
+
+	var compressor Compressor
+	switch compression {
+	case "", "none":
+		return codec, nil
+	case "gzip":
+		compressor = &GzipCompressor{}
+	case "zstd":
+		compressor = &ZstdCompressor{}
+	default:
+		return nil, fmt.Errorf("unsupported cache compression %s", compression)
+	}
+
+	return Compressed(codec, compressor, threshold), nil
+}
+
+// JSONCodec encodes values as JSON.
+type JSONCodec struct {
+}
+
+func (c *JSONCodec) Name() string {
+	return "json"
+}
+
+func (c *JSONCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (c *JSONCodec) Unmarshal(data []byte, target interface{}) error {
+	return json.Unmarshal(data, target)
+}
+
+// GobCodec encodes values with encoding/gob. Gob values keep their Go types, so they can
+// only be decoded into typed targets, e.g. with cache.GetAs, not into an interface{}.
+type GobCodec struct {
+}
+
+func (c *GobCodec) Name() string {
+	return "gob"
+}
+
+func (c *GobCodec) Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *GobCodec) Unmarshal(data []byte, target interface{}) error {
+	if _, ok := target.(*interface{}); ok {
+		return errors.New("gob values can only be decoded into typed targets")
+	}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(target)
+}
+
+// MsgpackCodec encodes values as MessagePack, a compact binary JSON.
+type MsgpackCodec struct {
+}
+
+func (c *MsgpackCodec) Name() string {
+	return "msgpack"
+}
+
+func (c *MsgpackCodec) Marshal(value interface{}) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (c *MsgpackCodec) Unmarshal(data []byte, target interface{}) error {
+	return msgpack.Unmarshal(data, target)
+}
+
+// RawCodec stores []byte and string values as they are.
+type RawCodec struct {
+}
+
+func (c *RawCodec) Name() string {
+	return "raw"
+}
+
+func (c *RawCodec) Marshal(value interface{}) ([]byte, error) {
+	switch value := value.(type) {
+	case []byte:
+		return value, nil
+	case string:
+		return []byte(value), nil
+	default:
+		return nil, fmt.Errorf("raw codec cannot encode %T: expected []byte or string", value)
+	}
+}
+
+func (c *RawCodec) Unmarshal(data []byte, target interface{}) error {
+	switch target := target.(type) {
+	case *[]byte:
+		*target = append([]byte(nil), data...)
+	case *string:
+		*target = string(data)
+	case *interface{}:
+		*target = append([]byte(nil), data...)
+	default:
+		return fmt.Errorf("raw codec cannot decode into %T: expected *[]byte or *string", target)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cache_codecs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// item is a typed value of the tests
+type item struct {
+	Name  string
+	Tags  []string
+	Count int
+}
+
+func TestRoundTrip(t *testing.T) {
+	small := item{Name: "small", Tags: []string{"a", "b"}, Count: 3}
+	large := item{Name: strings.Repeat("large ", 500), Tags: []string{"c"}, Count: 7}
+
+	// newTarget returns a pointer to decode a value of the codec into
+	tests := []struct {
+		codec     string
+		values    []interface{}
+		newTarget func() interface{}
+	}{
+		{codec: "json", values: []interface{}{small, large}, newTarget: func() interface{} { return &item{} }},
+		{codec: "gob", values: []interface{}{small, large}, newTarget: func() interface{} { return &item{} }},
+		{codec: "msgpack", values: []interface{}{small, large}, newTarget: func() interface{} { return &item{} }},
+		{codec: "raw", values: []interface{}{[]byte("small"), []byte(large.Name)}, newTarget: func() interface{} { return &[]byte{} }},
+	}
+
+	for _, tt := range tests {
+		for _, compression := range []string{"none", "gzip", "zstd"} {
+			t.Run(tt.codec+"/"+compression, func(t *testing.T) {
+				codec, err := New(tt.codec, compression, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, value := range tt.values {
+					data, err := codec.Marshal(value)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					target := tt.newTarget()
+					if err := codec.Unmarshal(data, target); err != nil {
+						t.Fatal(err)
+					}
+					if got := reflect.ValueOf(target).Elem().Interface(); !reflect.DeepEqual(got, value) {
+						t.Fatalf("decoded %v, want %v", got, value)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	large := strings.Repeat("compressible ", 200)
+
+	tests := []struct {
+		name           string
+		compression    string
+		value          string
+		wantHeader     byte
+		wantCompressed bool // Shorter than the encoded value
+	}{
+		{name: "gzip under the threshold", compression: "gzip", value: "short", wantHeader: plainHeader},
+		{name: "gzip above the threshold", compression: "gzip", value: large, wantHeader: compressedHeader, wantCompressed: true},
+		{name: "zstd under the threshold", compression: "zstd", value: "short", wantHeader: plainHeader},
+		{name: "zstd above the threshold", compression: "zstd", value: large, wantHeader: compressedHeader, wantCompressed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := New("raw", tt.compression, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := codec.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data[0] != tt.wantHeader || (len(data) < len(tt.value)) != tt.wantCompressed {
+				t.Fatalf("header %d and %d bytes for a value of %d bytes", data[0], len(data), len(tt.value))
+			}
+		})
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "unknown codec", run: func() error { _, err := New("xml", "", 0); return err }},
+		{name: "unknown compression", run: func() error { _, err := New("json", "lz4", 0); return err }},
+		{name: "raw value of another type", run: func() error { _, err := (&RawCodec{}).Marshal(1); return err }},
+		{name: "raw target of another type", run: func() error { var target int; return (&RawCodec{}).Unmarshal([]byte("1"), &target) }},
+		{name: "gob into an interface", run: func() error {
+			data, err := (&GobCodec{}).Marshal("value")
+			if err != nil {
+				return nil
+			}
+			var target interface{}
+			return (&GobCodec{}).Unmarshal(data, &target)
+		}},
+		{name: "missing compression header", run: func() error {
+			var target string
+			return Compressed(&RawCodec{}, &GzipCompressor{}, 0).Unmarshal(nil, &target)
+		}},
+		{name: "invalid compression header", run: func() error {
+			var target string
+			return Compressed(&RawCodec{}, &GzipCompressor{}, 0).Unmarshal([]byte{9, 'a'}, &target)
+		}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.run(); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package cache_codecs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressThreshold is the size from which encoded values are compressed
+const DefaultCompressThreshold = 1024
+
+// Headers of the values encoded by a compressed codec
+const (
+	plainHeader      byte = 0
+	compressedHeader byte = 1
+)
+
+// Compressor compresses encoded values.
+type Compressor interface {
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// compressedCodec compresses the values of a codec above a size threshold
+type compressedCodec struct {
+	codec      Codec
+	compressor Compressor
+	threshold  int
+}
+
+// Compressed returns a codec compressing the values of codec of at least threshold bytes,
+// DefaultCompressThreshold if threshold is zero or less. A header byte tells compressed values apart.
+func Compressed(codec Codec, compressor Compressor, threshold int) Codec {
+	if threshold <= 0 {
+		threshold = DefaultCompressThreshold
+	}
+
+	return &compressedCodec{
+		codec:      codec,
+		compressor: compressor,
+		threshold:  threshold,
+	}
+}
+
+func (c *compressedCodec) Name() string {
+	return c.codec.Name() + "+" + c.compressor.Name()
+}
+
+func (c *compressedCodec) Marshal(value interface{}) ([]byte, error) {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < c.threshold {
+		return append([]byte{plainHeader}, data...), nil
+	}
+
+	compressed, err := c.compressor.Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{compressedHeader}, compressed...), nil
+}
+
+func (c *compressedCodec) Unmarshal(data []byte, target interface{}) error {
+	if len(data) == 0 {
+		return errors.New("missing compression header")
+	}
+
+	switch data[0] {
+	case plainHeader:
+		return c.codec.Unmarshal(data[1:], target)
+	case compressedHeader:
+		decompressed, err := c.compressor.Decompress(data[1:])
+		if err != nil {
+			return err
+		}
+		return c.codec.Unmarshal(decompressed, target)
+	default:
+		return errors.New("invalid compression header")
+	}
+}
+
+// GzipCompressor compresses values with gzip.
+type GzipCompressor struct {
+}
+
+func (c *GzipCompressor) Name() string {
+	return "gzip"
+}
+
+func (c *GzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *GzipCompressor) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// ZstdCompressor compresses values with Zstandard.
+type ZstdCompressor struct {
+	once    sync.Once
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	err     error
+}
+
+func (c *ZstdCompressor) Name() string {
+	return "zstd"
+}
+
+// init creates the encoder and the decoder, which are safe for concurrent use
+func (c *ZstdCompressor) init() error {
+	c.once.Do(func() {
+		if c.encoder, c.err = zstd.NewWriter(nil); c.err != nil {
+			return
+		}
+		c.decoder, c.err = zstd.NewReader(nil)
+	})
+	return c.err
+}
+
+func (c *ZstdCompressor) Compress(data []byte) ([]byte, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c *ZstdCompressor) Decompress(data []byte) ([]byte, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c.decoder.DecodeAll(data, nil)
+}
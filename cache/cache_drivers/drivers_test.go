@@ -0,0 +1,184 @@
+package cache_drivers
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_models"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// record is a typed value of the untyped API, registered for gob
+type record struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	gob.Register(record{})
+}
+
+// testDriver is a driver of the untyped API under test
+type testDriver interface {
+	Name() string
+	Set(key string, value interface{}, expiration time.Duration) error
+	Get(key string) (interface{}, error)
+}
+
+// newDrivers returns a map, a file and a redis driver encoding their values with the codec
+func newDrivers(t *testing.T, codec cache_models.CodecConfig) []testDriver {
+	t.Helper()
+
+	mapDriver := &MapCacheDriver{}
+	if err := mapDriver.Init(cache_models.MapCacheConfig{CodecConfig: codec}); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, _ := os.Getwd()
+	path, err := filepath.Rel(wd, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileDriver := &FileCacheDriver{}
+	if err := fileDriver.Init(cache_models.FileCacheConfig{Path: path, CodecConfig: codec}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := miniredis.RunT(t)
+	redisDriver := &RedisCacheDriver{}
+	if err := redisDriver.Init(cache_models.RedisCacheConfig{Host: server.Host(), Port: server.Port(), CodecConfig: codec}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { redisDriver.getClient().Close() })
+
+	return []testDriver{mapDriver, fileDriver, redisDriver}
+}
+
+func TestUntypedCodecs(t *testing.T) {
+	large := strings.Repeat("large ", 500)
+
+	tests := []struct {
+		name   string
+		codec  cache_models.CodecConfig
+		values []interface{}
+		want   []interface{} // The values read back, the values themselves if nil
+	}{
+		{
+			name:   "gob",
+			codec:  cache_models.CodecConfig{Codec: "gob"},
+			values: []interface{}{"value", 42, []byte("bytes"), map[string]interface{}{"name": "a"}, record{Name: "b", Count: 2}},
+		},
+		{
+			name:   "gob gzip",
+			codec:  cache_models.CodecConfig{Codec: "gob", Compression: "gzip", CompressThreshold: 64},
+			values: []interface{}{"value", large, record{Name: large, Count: 3}},
+		},
+		{
+			name:   "gob zstd",
+			codec:  cache_models.CodecConfig{Codec: "gob", Compression: "zstd", CompressThreshold: 64},
+			values: []interface{}{"value", large, record{Name: large, Count: 3}},
+		},
+		{
+			name:   "json",
+			codec:  cache_models.CodecConfig{Codec: "json", Compression: "zstd", CompressThreshold: 64},
+			values: []interface{}{"value", 42, record{Name: large, Count: 3}},
+			want:   []interface{}{"value", float64(42), map[string]interface{}{"Name": large, "Count": float64(3)}},
+		},
+		{
+			name:   "msgpack",
+			codec:  cache_models.CodecConfig{Codec: "msgpack", Compression: "gzip", CompressThreshold: 64},
+			values: []interface{}{"value", large, []interface{}{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == nil {
+				want = tt.values
+			}
+
+			for _, driver := range newDrivers(t, tt.codec) {
+				for i, value := range tt.values {
+					if err := driver.Set("key", value, time.Minute); err != nil {
+						t.Fatalf("%s: Set %T: %v", driver.Name(), value, err)
+					}
+
+					got, err := driver.Get("key")
+					if err != nil {
+						t.Fatalf("%s: Get %T: %v", driver.Name(), value, err)
+					}
+					if !reflect.DeepEqual(got, want[i]) {
+						t.Fatalf("%s: Get = %#v, want %#v", driver.Name(), got, want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestUntypedValuesWithoutCodec(t *testing.T) {
+	drivers := newDrivers(t, cache_models.CodecConfig{})
+
+	// Redis formats the values as strings
+	tests := []struct {
+		value interface{}
+		want  map[string]interface{}
+	}{
+		{value: []byte("bytes"), want: map[string]interface{}{"map": []byte("bytes"), "file": []byte("bytes"), "redis": "bytes"}},
+		{value: "text", want: map[string]interface{}{"map": "text", "file": "text", "redis": "text"}},
+		{value: map[string]interface{}{"name": "a"}, want: map[string]interface{}{"map": map[string]interface{}{"name": "a"}, "file": map[string]interface{}{"name": "a"}}},
+	}
+
+	for _, tt := range tests {
+		for _, driver := range drivers {
+			want, ok := tt.want[driver.Name()]
+			if !ok {
+				continue
+			}
+
+			if err := driver.Set("key", tt.value, time.Minute); err != nil {
+				t.Fatalf("%s: Set %T: %v", driver.Name(), tt.value, err)
+			}
+
+			got, err := driver.Get("key")
+			if err != nil {
+				t.Fatalf("%s: Get %T: %v", driver.Name(), tt.value, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: Get = %#v, want %#v", driver.Name(), got, want)
+			}
+		}
+	}
+}
+
+func TestTagIndexPrunesExpiredKeys(t *testing.T) {
+	index := newTagIndex()
+	index.add([]string{"kept"}, time.Time{})
+
+	// Expired keys are dropped once the index reaches its pruning size
+	expired := time.Now().Add(-time.Second)
+	for i := 2; i < minTagPrune; i++ {
+		index.add([]string{strings.Repeat("x", i)}, expired)
+	}
+	if len(index.Keys) != minTagPrune-1 {
+		t.Fatalf("%d keys before pruning, want %d", len(index.Keys), minTagPrune-1)
+	}
+
+	index.add([]string{"later"}, time.Now().Add(time.Minute))
+
+	want := []string{"kept", "later"}
+	if len(index.Keys) != len(want) {
+		t.Fatalf("%d keys after pruning, want %v", len(index.Keys), want)
+	}
+	for _, key := range want {
+		if _, found := index.Keys[key]; !found {
+			t.Fatalf("key %s was pruned", key)
+		}
+	}
+}
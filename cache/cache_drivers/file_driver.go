@@ -1,9 +1,10 @@
 package cache_drivers
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/HemendCo/go-core/cache/cache_codecs"
 	"github.com/HemendCo/go-core/cache/cache_models"
 	"github.com/HemendCo/go-core/filemanager"
 	"github.com/HemendCo/go-core/helpers"
@@ -16,12 +17,19 @@ import (
 // fileCacheItem holds the data with expiration time
 type fileCacheItem struct {
 	Value      interface{} `json:"value"`
-	Expiration time.Time   `json:"expiration"`
+	Data       []byte      `json:"data"`       // Bytes stored by Store, encoded values, or []byte values without a codec
+	Expiration time.Time   `json:"expiration"` // Zero if the data does not expire
+}
+
+// expired reports whether the item expired.
+func (i fileCacheItem) expired() bool {
+	return !i.Expiration.IsZero() && time.Now().After(i.Expiration)
 }
 
 // FileCacheDriver structure for file-based caching
 type FileCacheDriver struct {
 	cfg         *cache_models.FileCacheConfig
+	codec       cache_codecs.Codec // Encodes the values of Set, nil to store them as JSON values
 	path        string
 	fileManager *filemanager.FileManager
 	mu          sync.RWMutex
@@ -43,32 +51,43 @@ func (f *FileCacheDriver) Init(config interface{}) error {
 		return errors.New("invalid file cache configuration: expected a cache_models.FileCacheConfig type")
 	}
 
+	codec, err := newCodec(cfg.CodecConfig, cfg.Serialize)
+	if err != nil {
+		return err
+	}
+
+	path := helpers.JoinWithProjectPath(cfg.Path)
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
+	}
+
 	f.cfg = &cfg
+	f.codec = codec
 	f.fileManager = filemanager.NewFileManager() // Initialize FileManager
-	f.path = helpers.JoinWithProjectPath(f.cfg.Path)
+	f.path = path
 
 	return nil
 }
 
+// Codec returns the codec of the values, or nil if they are stored as JSON values.
+func (f *FileCacheDriver) Codec() cache_codecs.Codec {
+	return f.codec
+}
+
+// Health checks that the cache directory is writable
+func (f *FileCacheDriver) Health(ctx context.Context) error {
+	return helpers.CheckWritableDir(f.path)
+}
+
 // Set stores data in a file
 func (f *FileCacheDriver) Set(key string, value interface{}, expiration time.Duration) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// If serialization is enabled, the value will be serialized
-	if f.cfg.Serialize {
-		serializedValue, err := json.Marshal(value)
-		if err != nil {
-			return err
-		}
-		value = serializedValue
-	}
-
 	// Set expiration time
-	exp := time.Now().Add(expiration)
-	item := fileCacheItem{
-		Value:      value,
-		Expiration: exp,
+	item, err := f.newItem(value, time.Now().Add(expiration))
+	if err != nil {
+		return err
 	}
 
 	// Create a file name based on the key
@@ -80,8 +99,9 @@ func (f *FileCacheDriver) Set(key string, value interface{}, expiration time.Dur
 
 // Get retrieves data from a file
 func (f *FileCacheDriver) Get(key string) (interface{}, error) {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
+	// Expired files are removed, which needs the write lock
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
 	// Create a file name based on the key
 	filePath := f.getFilePathForKey(key)
@@ -98,29 +118,18 @@ func (f *FileCacheDriver) Get(key string) (interface{}, error) {
 	}
 
 	// If the expiration time has passed, remove the value
-	if time.Now().After(item.Expiration) {
-		f.Delete(key) // Remove the expired file
+	if item.expired() {
+		f.fileManager.RemoveFileOrDirectory(filePath) // Remove the expired file
 		return nil, errors.New("key expired")
 	}
 
-	// If serialization is enabled, the value will be deserialized
-	if f.cfg.Serialize {
-		var deserializedValue interface{}
-		err := json.Unmarshal(item.Value.([]byte), &deserializedValue)
-		if err != nil {
-			return nil, err
-		}
-		return deserializedValue, nil
-	}
-
-	return item.Value, nil
+	return f.itemValue(key, item)
 }
 
 // Has checks if a key exists in the filesystem
 func (f *FileCacheDriver) Has(key string) (bool, error) {
-	if f.mu.TryLock() {
-		f.mu.Unlock()
-	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
 	// Create a file name based on the key
 	filePath := f.getFilePathForKey(key)
@@ -131,9 +140,8 @@ func (f *FileCacheDriver) Has(key string) (bool, error) {
 
 // Delete removes data from the file
 func (f *FileCacheDriver) Delete(key string) error {
-	if f.mu.TryLock() {
-		f.mu.Unlock()
-	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
 	// Create a file name based on the key
 	filePath := f.getFilePathForKey(key)
@@ -142,8 +150,332 @@ func (f *FileCacheDriver) Delete(key string) error {
 	return f.fileManager.RemoveFileOrDirectory(filePath)
 }
 
+// Modify replaces the value of key with the value returned by fn. The update is atomic within
+// the process; processes sharing the cache directory may overwrite each other.
+func (f *FileCacheDriver) Modify(key string, fn func(value interface{}) (interface{}, time.Duration, error)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	filePath := f.getFilePathForKey(key)
+
+	var current interface{}
+	var item fileCacheItem
+	err := f.fileManager.ReadFile(filePath, &item)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err == nil && !item.expired() {
+		if current, err = f.itemValue(key, item); err != nil {
+			return err
+		}
+	}
+
+	value, expiration, err := fn(current)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		return f.fileManager.RemoveFileOrDirectory(filePath)
+	}
+
+	item, err = f.newItem(value, time.Now().Add(expiration))
+	if err != nil {
+		return err
+	}
+
+	return f.fileManager.WriteFile(filePath, item)
+}
+
+// newItem creates the file content of a value, encoded by the codec if any
+func (f *FileCacheDriver) newItem(value interface{}, expiration time.Time) (fileCacheItem, error) {
+	if f.codec == nil {
+		// Bytes would read back as a base64 string from the JSON value
+		if data, ok := value.([]byte); ok && data != nil {
+			return fileCacheItem{Data: data, Expiration: expiration}, nil
+		}
+		return fileCacheItem{Value: value, Expiration: expiration}, nil
+	}
+
+	data, err := encode(f.codec, value)
+	if err != nil {
+		return fileCacheItem{}, err
+	}
+	return fileCacheItem{Data: data, Expiration: expiration}, nil
+}
+
+// itemValue returns the value of the file content, decoded by the codec if any
+func (f *FileCacheDriver) itemValue(key string, item fileCacheItem) (interface{}, error) {
+	if f.codec == nil {
+		if item.Data != nil {
+			return item.Data, nil
+		}
+		return item.Value, nil
+	}
+
+	if item.Data == nil {
+		return nil, fmt.Errorf("value of %s was not encoded", key)
+	}
+	return decode(f.codec, item.Data)
+}
+
+// Load returns the bytes stored under key by Store.
+func (f *FileCacheDriver) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.loadData(key)
+}
+
+// Store writes the bytes to the file of key.
+func (f *FileCacheDriver) Store(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.storeData(key, value, expiration(ttl))
+}
+
+// Exists checks if the file of key exists and has not expired.
+func (f *FileCacheDriver) Exists(ctx context.Context, key string) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	_, found, err := f.readItem(key)
+	return found, err
+}
+
+// Remove removes the file of key.
+func (f *FileCacheDriver) Remove(ctx context.Context, key string) error {
+	return f.Delete(key)
+}
+
+// TTL returns the remaining time to live of key, NoExpiration if it does not expire.
+func (f *FileCacheDriver) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	item, found, err := f.readItem(key)
+	if err != nil || !found {
+		return 0, false, err
+	}
+
+	return remaining(item.Expiration), true, nil
+}
+
+// LoadMany returns the bytes of the keys that exist and did not expire.
+func (f *FileCacheDriver) LoadMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, found, err := f.loadData(key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// StoreMany writes the bytes of every key to its file.
+func (f *FileCacheDriver) StoreMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key, value := range values {
+		if err := f.storeData(key, value, expiration(ttl)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveMany removes the files of the keys.
+func (f *FileCacheDriver) RemoveMany(ctx context.Context, keys []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range keys {
+		if err := f.fileManager.RemoveFileOrDirectory(f.getFilePathForKey(key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IncrementBy adds delta to the counter of key, which starts at zero and keeps its ttl.
+// The update is atomic within the process.
+func (f *FileCacheDriver) IncrementBy(ctx context.Context, key string, delta int64) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, found, err := f.readItem(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	var exp time.Time
+	if found {
+		if item.Data == nil {
+			return 0, fmt.Errorf("value of %s was not stored as bytes", key)
+		}
+		if current, err = parseCounter(key, item.Data); err != nil {
+			return 0, err
+		}
+		exp = item.Expiration
+	}
+
+	current += delta
+	if err := f.storeData(key, formatCounter(current), exp); err != nil {
+		return 0, err
+	}
+
+	return current, nil
+}
+
+// StoreIfAbsent writes the bytes unless key exists, and reports whether they were written.
+// The check is atomic within the process.
+func (f *FileCacheDriver) StoreIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, found, err := f.readItem(key)
+	if err != nil || found {
+		return false, err
+	}
+
+	return true, f.storeData(key, value, expiration(ttl))
+}
+
+// LoadAndRemove returns and removes the bytes of key, atomically within the process.
+func (f *FileCacheDriver) LoadAndRemove(ctx context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, found, err := f.loadData(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := f.fileManager.RemoveFileOrDirectory(f.getFilePathForKey(key)); err != nil {
+		return nil, false, err
+	}
+
+	return value, found, nil
+}
+
+// TagKeys adds the keys to the index file of every tag. The update is atomic within the process.
+func (f *FileCacheDriver) TagKeys(ctx context.Context, tags []string, keys []string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, tag := range tags {
+		index, err := f.readTagIndex(tag)
+		if err != nil {
+			return err
+		}
+
+		index.add(keys, expiration(ttl))
+		if err := f.fileManager.WriteFile(f.getFilePathForTag(tag), index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FlushTags removes the files of the keys indexed by any of the tags, and the index files.
+func (f *FileCacheDriver) FlushTags(ctx context.Context, tags []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, tag := range tags {
+		index, err := f.readTagIndex(tag)
+		if err != nil {
+			return err
+		}
+
+		for key := range index.Keys {
+			if err := f.fileManager.RemoveFileOrDirectory(f.getFilePathForKey(key)); err != nil {
+				return err
+			}
+		}
+
+		if err := f.fileManager.RemoveFileOrDirectory(f.getFilePathForTag(tag)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTagIndex reads the index file of a tag, empty if it does not exist
+func (f *FileCacheDriver) readTagIndex(tag string) (*tagIndex, error) {
+	index := newTagIndex()
+	if err := f.fileManager.ReadFile(f.getFilePathForTag(tag), index); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if index.Keys == nil {
+		index.Keys = make(map[string]time.Time)
+	}
+	return index, nil
+}
+
+// readItem reads the file of key and reports whether it exists and did not expire
+func (f *FileCacheDriver) readItem(key string) (fileCacheItem, bool, error) {
+	var item fileCacheItem
+	if err := f.fileManager.ReadFile(f.getFilePathForKey(key), &item); err != nil {
+		if os.IsNotExist(err) {
+			return item, false, nil
+		}
+		return item, false, err
+	}
+
+	return item, !item.expired(), nil
+}
+
+// loadData returns the bytes stored under key by Store
+func (f *FileCacheDriver) loadData(key string) ([]byte, bool, error) {
+	item, found, err := f.readItem(key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	if item.Data == nil {
+		return nil, false, fmt.Errorf("value of %s was not stored as bytes", key)
+	}
+
+	return item.Data, true, nil
+}
+
+// storeData writes the bytes of key
+func (f *FileCacheDriver) storeData(key string, value []byte, expiration time.Time) error {
+	if value == nil {
+		value = []byte{}
+	}
+
+	return f.fileManager.WriteFile(f.getFilePathForKey(key), fileCacheItem{
+		Data:       value,
+		Expiration: expiration,
+	})
+}
+
 // getFilePathForKey constructs the file path based on the key
 func (f *FileCacheDriver) getFilePathForKey(key string) string {
 	// Use the directory path and file name (constructed from the key)
 	return filepath.Join(f.path, fmt.Sprintf("%s.cache", key))
 }
+
+// getFilePathForTag constructs the path of the index file of a tag
+func (f *FileCacheDriver) getFilePathForTag(tag string) string {
+	return filepath.Join(f.path, fmt.Sprintf("%s.tag", tag))
+}
@@ -0,0 +1,128 @@
+package cache_drivers
+
+import (
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_codecs"
+	"github.com/HemendCo/go-core/cache/cache_models"
+)
+
+// newCodec returns the codec of a driver configuration, or nil if values are stored as they are.
+// The legacy serialize flag selects the JSON codec.
+func newCodec(cfg cache_models.CodecConfig, serialize bool) (cache_codecs.Codec, error) {
+	name := cfg.Codec
+	if name == "" && serialize {
+		name = "json"
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	return cache_codecs.New(name, cfg.Compression, cfg.CompressThreshold)
+}
+
+func init() {
+	// Generic values of the untyped API, e.g. decoded JSON
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// gobValue carries a value of the untyped API through the gob codec, which only decodes into
+// typed targets. Gob encodes the concrete type of the value, which must be registered with gob.Register.
+type gobValue struct {
+	Value interface{}
+}
+
+// isGob reports whether the codec encodes values with gob, compressed or not
+func isGob(codec cache_codecs.Codec) bool {
+	name, _, _ := strings.Cut(codec.Name(), "+")
+	return name == "gob"
+}
+
+// encode encodes a value of the untyped API with a codec
+func encode(codec cache_codecs.Codec, value interface{}) ([]byte, error) {
+	if isGob(codec) {
+		return codec.Marshal(gobValue{Value: value})
+	}
+	return codec.Marshal(value)
+}
+
+// decode decodes a value encoded by encode into a generic value
+func decode(codec cache_codecs.Codec, data []byte) (interface{}, error) {
+	if isGob(codec) {
+		var value gobValue
+		if err := codec.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return value.Value, nil
+	}
+
+	var value interface{}
+	if err := codec.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// remaining returns the time to live until the expiration time, NoExpiration for the zero time
+func remaining(expiration time.Time) time.Duration {
+	if expiration.IsZero() {
+		return cache_models.NoExpiration
+	}
+	return max(0, time.Until(expiration))
+}
+
+// parseCounter parses a counter stored as decimal text
+func parseCounter(key string, value []byte) (int64, error) {
+	counter, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of %s is not a counter", key)
+	}
+	return counter, nil
+}
+
+// formatCounter formats a counter as decimal text
+func formatCounter(counter int64) []byte {
+	return strconv.AppendInt(nil, counter, 10)
+}
+
+// tagIndex holds the keys indexed by a tag with their expiration time, zero if they do not expire
+type tagIndex struct {
+	Keys    map[string]time.Time `json:"keys"`
+	pruneAt int                  // Size from which expired keys are dropped
+}
+
+// minTagPrune is the smallest size of a tag index from which expired keys are dropped
+const minTagPrune = 64
+
+// newTagIndex creates an empty tag index
+func newTagIndex() *tagIndex {
+	return &tagIndex{Keys: make(map[string]time.Time)}
+}
+
+// add indexes the keys until the expiration time. Expired keys are dropped whenever the
+// index doubles in size, so indexing stays amortized constant time.
+func (t *tagIndex) add(keys []string, expiration time.Time) {
+	for _, key := range keys {
+		t.Keys[key] = expiration
+	}
+
+	if len(t.Keys) >= t.pruneAt {
+		t.prune()
+		t.pruneAt = max(2*len(t.Keys), minTagPrune)
+	}
+}
+
+// prune drops the keys that expired
+func (t *tagIndex) prune() {
+	now := time.Now()
+	for key, expiration := range t.Keys {
+		if !expiration.IsZero() && now.After(expiration) {
+			delete(t.Keys, key)
+		}
+	}
+}
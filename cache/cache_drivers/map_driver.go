@@ -1,25 +1,41 @@
 package cache_drivers
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/HemendCo/go-core/cache/cache_codecs"
 	"github.com/HemendCo/go-core/cache/cache_models"
 )
 
 // mapCacheItem holds cached data along with its expiration time.
 type mapCacheItem struct {
 	value      interface{}
-	expiration time.Time
+	expiration time.Time // Zero if the data does not expire
+	size       int64     // Size counted against the max_bytes limit
+}
+
+// expired reports whether the item expired.
+func (i mapCacheItem) expired() bool {
+	return !i.expiration.IsZero() && time.Now().After(i.expiration)
 }
 
 // MapCacheDriver is a structure for in-memory caching.
 type MapCacheDriver struct {
-	cache map[string]mapCacheItem
-	cfg   *cache_models.MapCacheConfig
-	mu    sync.RWMutex
+	cache    map[string]mapCacheItem
+	tags     map[string]*tagIndex // Keys by tag
+	cfg      *cache_models.MapCacheConfig
+	codec    cache_codecs.Codec // Encodes the values of Set, nil to keep them as they are
+	eviction *evictionQueue     // Order of eviction, nil if the cache is not bounded
+	bytes    int64              // Size of the entries
+	stop     chan struct{}      // Stops the janitor, nil if it is not running
+	mu       sync.RWMutex
+
+	hits, misses, evictions, expirations atomic.Int64
 }
 
 // Name returns the name of the cache driver.
@@ -39,60 +55,155 @@ func (r *MapCacheDriver) Init(config interface{}) error {
 		return errors.New("invalid map cache configuration: expected a cache_models.MapCacheConfig type")
 	}
 
+	codec, err := newCodec(cfg.CodecConfig, cfg.Serialize)
+	if err != nil {
+		return err
+	}
+
+	eviction, err := newEvictionQueue(cfg.Eviction)
+	if err != nil {
+		return err
+	}
+	if cfg.MaxEntries > 0 || cfg.MaxBytes > 0 {
+		r.eviction = eviction
+	}
+
 	r.cfg = &cfg
+	r.codec = codec
 	r.cache = make(map[string]mapCacheItem)
+	r.tags = make(map[string]*tagIndex)
+
+	if cfg.CleanupInterval > 0 {
+		r.stop = make(chan struct{})
+		go r.janitor(cfg.CleanupInterval, r.stop)
+	}
+
+	return nil
+}
+
+// janitor removes the expired entries on every interval until stop is closed
+func (r *MapCacheDriver) janitor(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
+	for {
+		select {
+		case <-ticker.C:
+			r.PurgeExpired()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close stops the janitor.
+func (r *MapCacheDriver) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
 	return nil
 }
 
+// PurgeExpired removes the expired entries and the expired keys of the tag indexes,
+// and returns the number of entries removed.
+func (r *MapCacheDriver) PurgeExpired() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for key, item := range r.cache {
+		if item.expired() {
+			r.expire(key)
+			purged++
+		}
+	}
+
+	for tag, index := range r.tags {
+		index.prune()
+		if len(index.Keys) == 0 {
+			delete(r.tags, tag)
+		}
+	}
+
+	return purged
+}
+
+// Stats returns the number and size of the entries and the counters since Init.
+func (r *MapCacheDriver) Stats() cache_models.CacheStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cache_models.CacheStats{
+		Entries:     len(r.cache),
+		Bytes:       r.bytes,
+		Hits:        r.hits.Load(),
+		Misses:      r.misses.Load(),
+		Evictions:   r.evictions.Load(),
+		Expirations: r.expirations.Load(),
+	}
+}
+
+// Codec returns the codec of the values, or nil if they are stored as they are.
+func (r *MapCacheDriver) Codec() cache_codecs.Codec {
+	return r.codec
+}
+
 // Set stores data in the cache with an expiration time.
 func (r *MapCacheDriver) Set(key string, value interface{}, expiration time.Duration) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Serialize the value if serialization is enabled.
-	if r.cfg.Serialize {
-		serializedValue, err := json.Marshal(value)
+	// Encode the value if a codec is configured.
+	if r.codec != nil {
+		encodedValue, err := encode(r.codec, value)
 		if err != nil {
 			return err
 		}
-		value = serializedValue
+		value = encodedValue
 	}
 
 	// Set expiration time.
 	exp := time.Now().Add(expiration)
-	r.cache[key] = mapCacheItem{
+	r.put(key, mapCacheItem{
 		value:      value,
 		expiration: exp,
-	}
+	})
 
 	return nil
 }
 
 // Get retrieves data from the cache by key.
 func (r *MapCacheDriver) Get(key string) (interface{}, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	// Expired keys are removed, which needs the write lock.
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	item, found := r.cache[key]
 	if !found {
+		r.misses.Add(1)
 		return nil, nil // Key does not exist.
 	}
 
 	// Remove and return error if the key has expired.
-	if time.Now().After(item.expiration) {
-		delete(r.cache, key)
+	if item.expired() {
+		r.misses.Add(1)
+		r.expire(key)
 		return nil, errors.New("key expired")
 	}
 
-	// Deserialize the value if serialization is enabled.
-	if r.cfg.Serialize {
-		var deserializedValue interface{}
-		err := json.Unmarshal(item.value.([]byte), &deserializedValue)
-		if err != nil {
-			return nil, err
+	r.hit(key)
+
+	// Decode the value if a codec is configured.
+	if r.codec != nil {
+		data, ok := item.value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("value of %s was not encoded", key)
 		}
-		return deserializedValue, nil
+		return decode(r.codec, data)
 	}
 
 	return item.value, nil
@@ -100,8 +211,9 @@ func (r *MapCacheDriver) Get(key string) (interface{}, error) {
 
 // Has checks if a key exists in the cache and has not expired.
 func (r *MapCacheDriver) Has(key string) (bool, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	// Expired keys are removed, which needs the write lock.
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	item, found := r.cache[key]
 	if !found {
@@ -109,8 +221,8 @@ func (r *MapCacheDriver) Has(key string) (bool, error) {
 	}
 
 	// Remove expired key and return false.
-	if time.Now().After(item.expiration) {
-		delete(r.cache, key)
+	if item.expired() {
+		r.expire(key)
 		return false, nil
 	}
 
@@ -122,6 +234,357 @@ func (r *MapCacheDriver) Delete(key string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.cache, key)
+	r.delete(key)
+	return nil
+}
+
+// Modify atomically replaces the value of key with the value returned by fn.
+func (r *MapCacheDriver) Modify(key string, fn func(value interface{}) (interface{}, time.Duration, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var current interface{}
+	if item, found := r.cache[key]; found && !item.expired() {
+		current = item.value
+
+		// Decode the value if a codec is configured.
+		if r.codec != nil {
+			data, ok := item.value.([]byte)
+			if !ok {
+				return fmt.Errorf("value of %s was not encoded", key)
+			}
+
+			var err error
+			if current, err = decode(r.codec, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	value, expiration, err := fn(current)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		r.delete(key)
+		return nil
+	}
+
+	// Encode the value if a codec is configured.
+	if r.codec != nil {
+		encodedValue, err := encode(r.codec, value)
+		if err != nil {
+			return err
+		}
+		value = encodedValue
+	}
+
+	r.put(key, mapCacheItem{
+		value:      value,
+		expiration: time.Now().Add(expiration),
+	})
+
+	return nil
+}
+
+// Load returns the bytes stored under key by Store.
+func (r *MapCacheDriver) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, found := r.cache[key]
+	if !found || item.expired() {
+		r.misses.Add(1)
+		return nil, false, nil
+	}
+
+	value, ok := item.value.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("value of %s was not stored as bytes", key)
+	}
+
+	r.hit(key)
+	return append([]byte(nil), value...), true, nil
+}
+
+// Store stores a copy of the bytes under key.
+func (r *MapCacheDriver) Store(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.put(key, mapCacheItem{
+		value:      append([]byte(nil), value...),
+		expiration: expiration(ttl),
+	})
+
 	return nil
 }
+
+// Exists checks if a key exists in the cache and has not expired.
+func (r *MapCacheDriver) Exists(ctx context.Context, key string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, found := r.cache[key]
+	return found && !item.expired(), nil
+}
+
+// Remove removes data from the cache by key.
+func (r *MapCacheDriver) Remove(ctx context.Context, key string) error {
+	return r.Delete(key)
+}
+
+// TTL returns the remaining time to live of key, NoExpiration if it does not expire.
+func (r *MapCacheDriver) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, found := r.cache[key]
+	if !found || item.expired() {
+		return 0, false, nil
+	}
+
+	return remaining(item.expiration), true, nil
+}
+
+// LoadMany returns the bytes of the keys that exist and did not expire.
+func (r *MapCacheDriver) LoadMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		item, found := r.cache[key]
+		if !found || item.expired() {
+			r.misses.Add(1)
+			continue
+		}
+
+		value, ok := item.value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("value of %s was not stored as bytes", key)
+		}
+		values[key] = append([]byte(nil), value...)
+		r.hit(key)
+	}
+
+	return values, nil
+}
+
+// StoreMany stores copies of the bytes of every key.
+func (r *MapCacheDriver) StoreMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, value := range values {
+		r.put(key, mapCacheItem{
+			value:      append([]byte(nil), value...),
+			expiration: expiration(ttl),
+		})
+	}
+
+	return nil
+}
+
+// RemoveMany removes the keys.
+func (r *MapCacheDriver) RemoveMany(ctx context.Context, keys []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		r.delete(key)
+	}
+
+	return nil
+}
+
+// IncrementBy atomically adds delta to the counter of key, which starts at zero and keeps its ttl.
+func (r *MapCacheDriver) IncrementBy(ctx context.Context, key string, delta int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var current int64
+	var exp time.Time
+	if item, found := r.cache[key]; found && !item.expired() {
+		value, ok := item.value.([]byte)
+		if !ok {
+			return 0, fmt.Errorf("value of %s was not stored as bytes", key)
+		}
+
+		var err error
+		if current, err = parseCounter(key, value); err != nil {
+			return 0, err
+		}
+		exp = item.expiration
+	}
+
+	current += delta
+	r.put(key, mapCacheItem{
+		value:      formatCounter(current),
+		expiration: exp,
+	})
+
+	return current, nil
+}
+
+// StoreIfAbsent stores a copy of the bytes unless key exists, and reports whether they were stored.
+func (r *MapCacheDriver) StoreIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, found := r.cache[key]; found && !item.expired() {
+		return false, nil
+	}
+
+	r.put(key, mapCacheItem{
+		value:      append([]byte(nil), value...),
+		expiration: expiration(ttl),
+	})
+
+	return true, nil
+}
+
+// LoadAndRemove atomically returns and removes the bytes of key.
+func (r *MapCacheDriver) LoadAndRemove(ctx context.Context, key string) ([]byte, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, found := r.cache[key]
+	if !found {
+		return nil, false, nil
+	}
+
+	if item.expired() {
+		r.expire(key)
+		return nil, false, nil
+	}
+
+	value, ok := item.value.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("value of %s was not stored as bytes", key)
+	}
+	r.delete(key)
+
+	return value, true, nil
+}
+
+// TagKeys adds the keys to the index of every tag.
+func (r *MapCacheDriver) TagKeys(ctx context.Context, tags []string, keys []string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tag := range tags {
+		index, found := r.tags[tag]
+		if !found {
+			index = newTagIndex()
+			r.tags[tag] = index
+		}
+		index.add(keys, expiration(ttl))
+	}
+
+	return nil
+}
+
+// FlushTags removes the keys indexed by any of the tags, and the indexes.
+func (r *MapCacheDriver) FlushTags(ctx context.Context, tags []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tag := range tags {
+		index, found := r.tags[tag]
+		if !found {
+			continue
+		}
+
+		for key := range index.Keys {
+			r.delete(key)
+		}
+		delete(r.tags, tag)
+	}
+
+	return nil
+}
+
+// put stores the item of key, then evicts entries until the cache is within its limits
+func (r *MapCacheDriver) put(key string, item mapCacheItem) {
+	item.size = entrySize(key, item.value)
+	if current, found := r.cache[key]; found {
+		r.bytes -= current.size
+	}
+	r.cache[key] = item
+	r.bytes += item.size
+
+	if r.eviction == nil {
+		return
+	}
+
+	r.eviction.set(key, item.expiration)
+	for r.exceedsLimits() {
+		victim, ok := r.eviction.victim(key)
+		if !ok {
+			break
+		}
+
+		if r.cache[victim].expired() {
+			r.expire(victim)
+		} else {
+			r.delete(victim)
+			r.evictions.Add(1)
+		}
+	}
+}
+
+// exceedsLimits reports whether the cache holds more entries or bytes than configured
+func (r *MapCacheDriver) exceedsLimits() bool {
+	return (r.cfg.MaxEntries > 0 && len(r.cache) > r.cfg.MaxEntries) ||
+		(r.cfg.MaxBytes > 0 && r.bytes > r.cfg.MaxBytes)
+}
+
+// delete removes the item of key
+func (r *MapCacheDriver) delete(key string) {
+	item, found := r.cache[key]
+	if !found {
+		return
+	}
+
+	delete(r.cache, key)
+	r.bytes -= item.size
+	if r.eviction != nil {
+		r.eviction.remove(key)
+	}
+}
+
+// expire removes the expired item of key
+func (r *MapCacheDriver) expire(key string) {
+	r.delete(key)
+	r.expirations.Add(1)
+}
+
+// hit records a read of key
+func (r *MapCacheDriver) hit(key string) {
+	r.hits.Add(1)
+	if r.eviction != nil {
+		r.eviction.touch(key)
+	}
+}
+
+// entrySize returns the size of an entry: its key and its value if it is a string or bytes
+func entrySize(key string, value interface{}) int64 {
+	size := int64(len(key))
+	switch value := value.(type) {
+	case []byte:
+		size += int64(len(value))
+	case string:
+		size += int64(len(value))
+	}
+	return size
+}
+
+// expiration returns the expiration time of a ttl, or the zero time if the data does not expire.
+func expiration(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,334 @@
+package cache_drivers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_models"
+)
+
+// newMapDriver creates a map driver and stops its janitor at the end of the test
+func newMapDriver(t *testing.T, cfg cache_models.MapCacheConfig) *MapCacheDriver {
+	t.Helper()
+
+	driver := &MapCacheDriver{}
+	if err := driver.Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Close() })
+	return driver
+}
+
+// keys returns the sorted keys stored in the map driver, expired or not
+func keys(driver *MapCacheDriver) []string {
+	driver.mu.RLock()
+	defer driver.mu.RUnlock()
+
+	res := make([]string, 0, len(driver.cache))
+	for key := range driver.cache {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// mapOp stores a key with a ttl, or loads it if load is set
+type mapOp struct {
+	key   string
+	value string
+	ttl   time.Duration
+	load  bool
+}
+
+// stores returns operations storing the keys without expiration
+func stores(keys ...string) []mapOp {
+	ops := make([]mapOp, len(keys))
+	for i, key := range keys {
+		ops[i] = mapOp{key: key}
+	}
+	return ops
+}
+
+// loads returns operations loading the keys
+func loads(keys ...string) []mapOp {
+	ops := make([]mapOp, len(keys))
+	for i, key := range keys {
+		ops[i] = mapOp{key: key, load: true}
+	}
+	return ops
+}
+
+// concat returns the operations of every part in a new slice
+func concat(parts ...[]mapOp) []mapOp {
+	var ops []mapOp
+	for _, part := range parts {
+		ops = append(ops, part...)
+	}
+	return ops
+}
+
+func TestMapEviction(t *testing.T) {
+	ctx := context.Background()
+
+	// Three entries are used in the order a, a, c, b: a is the most used, c the least recently
+	// used among the least used and a the least recently used
+	used := concat(stores("a", "b", "c"), loads("a", "a", "c", "b"))
+
+	tests := []struct {
+		name          string
+		cfg           cache_models.MapCacheConfig
+		ops           []mapOp
+		want          []string
+		wantBytes     int64
+		wantEvictions int64
+	}{
+		{
+			name: "unbounded",
+			cfg:  cache_models.MapCacheConfig{},
+			ops:  concat(used, stores("d")),
+			want: []string{"a", "b", "c", "d"}, wantBytes: 4,
+		},
+		{
+			name: "lru by default",
+			cfg:  cache_models.MapCacheConfig{MaxEntries: 3},
+			ops:  concat(used, stores("d")),
+			want: []string{"b", "c", "d"}, wantBytes: 3, wantEvictions: 1,
+		},
+		{
+			name: "lfu",
+			cfg:  cache_models.MapCacheConfig{MaxEntries: 3, Eviction: cache_models.EvictLFU},
+			ops:  concat(used, stores("d")),
+			want: []string{"a", "b", "d"}, wantBytes: 3, wantEvictions: 1,
+		},
+		{
+			name: "lfu keeps frequently used keys over recent ones",
+			cfg:  cache_models.MapCacheConfig{MaxEntries: 3, Eviction: cache_models.EvictLFU},
+			ops:  concat(used, stores("d", "e")),
+			want: []string{"a", "b", "e"}, wantBytes: 3, wantEvictions: 2,
+		},
+		{
+			name: "ttl",
+			cfg:  cache_models.MapCacheConfig{MaxEntries: 3, Eviction: cache_models.EvictTTL},
+			ops:  []mapOp{{key: "hour", ttl: time.Hour}, {key: "forever"}, {key: "minute", ttl: time.Minute}, {key: "day", ttl: 24 * time.Hour}},
+			want: []string{"day", "forever", "hour"}, wantBytes: 14, wantEvictions: 1,
+		},
+		{
+			name: "ttl evicts keys without expiration last",
+			cfg:  cache_models.MapCacheConfig{MaxEntries: 2, Eviction: cache_models.EvictTTL},
+			ops:  []mapOp{{key: "a"}, {key: "b"}, {key: "hour", ttl: time.Hour}, {key: "c"}},
+			want: []string{"b", "c"}, wantBytes: 2, wantEvictions: 2,
+		},
+		{
+			name: "max bytes",
+			cfg:  cache_models.MapCacheConfig{MaxBytes: 20},
+			ops:  []mapOp{{key: "k1", value: "12345678"}, {key: "k2", value: "12345678"}, {key: "k3", value: "12345678"}},
+			want: []string{"k2", "k3"}, wantBytes: 20, wantEvictions: 1,
+		},
+		{
+			name: "overwrite counts the new size",
+			cfg:  cache_models.MapCacheConfig{MaxBytes: 20},
+			ops:  []mapOp{{key: "k1", value: "12345678"}, {key: "k2", value: "1"}, {key: "k2", value: "12345678"}},
+			want: []string{"k1", "k2"}, wantBytes: 20,
+		},
+		{
+			name: "entry larger than max bytes is kept alone",
+			cfg:  cache_models.MapCacheConfig{MaxBytes: 10},
+			ops:  []mapOp{{key: "k1", value: "1"}, {key: "k2", value: strings.Repeat("x", 20)}},
+			want: []string{"k2"}, wantBytes: 22, wantEvictions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := newMapDriver(t, tt.cfg)
+
+			for _, op := range tt.ops {
+				if op.load {
+					if _, _, err := driver.Load(ctx, op.key); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+				if err := driver.Store(ctx, op.key, []byte(op.value), op.ttl); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := keys(driver); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("keys %v, want %v", got, tt.want)
+			}
+			if stats := driver.Stats(); stats.Bytes != tt.wantBytes || stats.Evictions != tt.wantEvictions || stats.Entries != len(tt.want) {
+				t.Fatalf("stats %+v, want %d entries of %d bytes and %d evictions", stats, len(tt.want), tt.wantBytes, tt.wantEvictions)
+			}
+		})
+	}
+}
+
+func TestMapEvictsExpiredEntriesFirst(t *testing.T) {
+	ctx := context.Background()
+	driver := newMapDriver(t, cache_models.MapCacheConfig{MaxEntries: 2})
+
+	if err := driver.Store(ctx, "short", nil, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Store(ctx, "kept", nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := driver.Store(ctx, "new", nil, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The expired entry is the least recently used one and is counted as an expiration
+	if got := keys(driver); strings.Join(got, ",") != "kept,new" {
+		t.Fatalf("keys %v, want [kept new]", got)
+	}
+	if stats := driver.Stats(); stats.Evictions != 0 || stats.Expirations != 1 {
+		t.Fatalf("stats %+v, want 1 expiration and no eviction", stats)
+	}
+}
+
+func TestMapStats(t *testing.T) {
+	ctx := context.Background()
+	driver := newMapDriver(t, cache_models.MapCacheConfig{})
+
+	tests := []struct {
+		name string
+		run  func() error
+		want cache_models.CacheStats
+	}{
+		{
+			name: "store",
+			run:  func() error { return driver.Store(ctx, "key", []byte("value"), 0) },
+			want: cache_models.CacheStats{Entries: 1, Bytes: 8},
+		},
+		{
+			name: "hit",
+			run:  func() error { _, _, err := driver.Load(ctx, "key"); return err },
+			want: cache_models.CacheStats{Entries: 1, Bytes: 8, Hits: 1},
+		},
+		{
+			name: "untyped hit",
+			run:  func() error { _, err := driver.Get("key"); return err },
+			want: cache_models.CacheStats{Entries: 1, Bytes: 8, Hits: 2},
+		},
+		{
+			name: "miss",
+			run:  func() error { _, _, err := driver.Load(ctx, "missing"); return err },
+			want: cache_models.CacheStats{Entries: 1, Bytes: 8, Hits: 2, Misses: 1},
+		},
+		{
+			name: "store an expiring key",
+			run:  func() error { return driver.Store(ctx, "short", []byte("v"), time.Millisecond) },
+			want: cache_models.CacheStats{Entries: 2, Bytes: 14, Hits: 2, Misses: 1},
+		},
+		{
+			name: "read the expired key",
+			run: func() error {
+				time.Sleep(5 * time.Millisecond)
+				_, _, err := driver.Load(ctx, "short")
+				return err
+			},
+			// Reads under the read lock leave the expired entry to the janitor
+			want: cache_models.CacheStats{Entries: 2, Bytes: 14, Hits: 2, Misses: 2},
+		},
+		{
+			name: "purge",
+			run: func() error {
+				if purged := driver.PurgeExpired(); purged != 1 {
+					t.Fatalf("%d entries purged, want 1", purged)
+				}
+				return nil
+			},
+			want: cache_models.CacheStats{Entries: 1, Bytes: 8, Hits: 2, Misses: 2, Expirations: 1},
+		},
+		{
+			name: "remove",
+			run:  func() error { return driver.Remove(ctx, "key") },
+			want: cache_models.CacheStats{Hits: 2, Misses: 2, Expirations: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		if err := tt.run(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := driver.Stats(); got != tt.want {
+			t.Fatalf("%s: stats %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMapJanitor(t *testing.T) {
+	ctx := context.Background()
+	driver := newMapDriver(t, cache_models.MapCacheConfig{CleanupInterval: 5 * time.Millisecond})
+
+	if err := driver.Store(ctx, "short", []byte("v"), 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Store(ctx, "kept", []byte("v"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.TagKeys(ctx, []string{"group"}, []string{"short"}, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// The expired entry and its tag index are removed without being read
+	deadline := time.Now().Add(time.Second)
+	for driver.Stats().Expirations == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the janitor did not remove the expired entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := keys(driver); strings.Join(got, ",") != "kept" {
+		t.Fatalf("keys %v, want [kept]", got)
+	}
+
+	// The tag index is pruned on the next cleanup
+	deadline = time.Now().Add(time.Second)
+	for {
+		driver.mu.RLock()
+		_, found := driver.tags["group"]
+		driver.mu.RUnlock()
+		if !found {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the janitor did not remove the expired tag index")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := driver.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMapInvalidEviction(t *testing.T) {
+	driver := &MapCacheDriver{}
+	if err := driver.Init(cache_models.MapCacheConfig{MaxEntries: 1, Eviction: "random"}); err == nil {
+		t.Fatal("Init with an unknown eviction policy did not fail")
+	}
+}
+
+func TestMapLoadAndRemoveKeepsUntypedValues(t *testing.T) {
+	ctx := context.Background()
+	driver := newMapDriver(t, cache_models.MapCacheConfig{})
+
+	if err := driver.Set("key", "text", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// A value stored through the untyped API is not removed when it cannot be returned as bytes
+	if _, _, err := driver.LoadAndRemove(ctx, "key"); err == nil {
+		t.Fatal("LoadAndRemove of a value not stored as bytes did not fail")
+	}
+	if got, err := driver.Get("key"); err != nil || got != "text" {
+		t.Fatalf("Get = %v, %v after LoadAndRemove, want text", got, err)
+	}
+}
@@ -0,0 +1,164 @@
+package cache_drivers
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_models"
+)
+
+// evictionEntry holds what an eviction policy knows about a key
+type evictionEntry struct {
+	key        string
+	uses       int64     // Reads and writes of the key
+	used       uint64    // Sequence number of the last use
+	expiration time.Time // Zero if the key does not expire
+	index      int       // Position in the heap
+}
+
+// evictionQueue orders the keys of a bounded map cache, the next key to evict first.
+// It has its own lock so that reads can record their use under the read lock of the cache.
+type evictionQueue struct {
+	mu      sync.Mutex
+	entries []*evictionEntry
+	byKey   map[string]*evictionEntry
+	less    func(a, b *evictionEntry) bool
+	seq     uint64
+}
+
+// newEvictionQueue creates the queue of an eviction policy
+func newEvictionQueue(policy string) (*evictionQueue, error) {
+	q := &evictionQueue{byKey: make(map[string]*evictionEntry)}
+
+	switch policy {
+	case cache_models.EvictLRU, "":
+		q.less = func(a, b *evictionEntry) bool {
+			return a.used < b.used
+		}
+	case cache_models.EvictLFU:
+		q.less = func(a, b *evictionEntry) bool {
+			if a.uses != b.uses {
+				return a.uses < b.uses
+			}
+			return a.used < b.used
+		}
+	case cache_models.EvictTTL:
+		q.less = func(a, b *evictionEntry) bool {
+			switch {
+			case a.expiration.IsZero() != b.expiration.IsZero():
+				return b.expiration.IsZero()
+			case !a.expiration.Equal(b.expiration):
+				return a.expiration.Before(b.expiration)
+			}
+			return a.used < b.used
+		}
+	default:
+		return nil, fmt.Errorf("unsupported map cache eviction policy: %s", policy)
+	}
+
+	return q, nil
+}
+
+// set records a write of key, expiring at the expiration time
+func (q *evictionQueue) set(key string, expiration time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	if entry, found := q.byKey[key]; found {
+		entry.uses++
+		entry.used = q.seq
+		entry.expiration = expiration
+		heap.Fix(q, entry.index)
+		return
+	}
+
+	entry := &evictionEntry{key: key, uses: 1, used: q.seq, expiration: expiration}
+	q.byKey[key] = entry
+	heap.Push(q, entry)
+}
+
+// touch records a read of key
+func (q *evictionQueue) touch(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if entry, found := q.byKey[key]; found {
+		q.seq++
+		entry.uses++
+		entry.used = q.seq
+		heap.Fix(q, entry.index)
+	}
+}
+
+// remove forgets key
+func (q *evictionQueue) remove(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if entry, found := q.byKey[key]; found {
+		heap.Remove(q, entry.index)
+		delete(q.byKey, key)
+	}
+}
+
+// victim returns the next key to evict other than except, and whether there is one
+func (q *evictionQueue) victim(except string) (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// The smallest entry is the root; if it is excluded, the next one is one of its children.
+	var best *evictionEntry
+	for i := 0; i < len(q.entries) && i <= 2; i++ {
+		entry := q.entries[i]
+		if entry.key == except {
+			continue
+		}
+		if best == nil || q.less(entry, best) {
+			best = entry
+		}
+		if i == 0 {
+			break
+		}
+	}
+
+	if best == nil {
+		return "", false
+	}
+	return best.key, true
+}
+
+// Len implements heap.Interface.
+func (q *evictionQueue) Len() int {
+	return len(q.entries)
+}
+
+// Less implements heap.Interface.
+func (q *evictionQueue) Less(i, j int) bool {
+	return q.less(q.entries[i], q.entries[j])
+}
+
+// Swap implements heap.Interface.
+func (q *evictionQueue) Swap(i, j int) {
+	q.entries[i], q.entries[j] = q.entries[j], q.entries[i]
+	q.entries[i].index = i
+	q.entries[j].index = j
+}
+
+// Push implements heap.Interface.
+func (q *evictionQueue) Push(x interface{}) {
+	entry := x.(*evictionEntry)
+	entry.index = len(q.entries)
+	q.entries = append(q.entries, entry)
+}
+
+// Pop implements heap.Interface.
+func (q *evictionQueue) Pop() interface{} {
+	last := len(q.entries) - 1
+	entry := q.entries[last]
+	q.entries[last] = nil
+	q.entries = q.entries[:last]
+	return entry
+}
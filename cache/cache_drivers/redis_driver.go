@@ -3,8 +3,10 @@ package cache_drivers
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/HemendCo/go-core/cache/cache_codecs"
 	"github.com/HemendCo/go-core/cache/cache_models"
 
 	"context"
@@ -12,12 +14,44 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-var ctx = context.Background()
+// redisTagPrefix prefixes the sets holding the keys of a tag
+const redisTagPrefix = "cache_tag:"
+
+// tagScript adds ARGV[2..] to the tag sets KEYS and extends their ttl to ARGV[1] milliseconds,
+// or removes it if ARGV[1] is 0, so a tag set lives as long as its keys
+var tagScript = redis.NewScript(`
+local ttl = tonumber(ARGV[1])
+for _, tag in ipairs(KEYS) do
+	local current = redis.call("PTTL", tag)
+	redis.call("SADD", tag, unpack(ARGV, 2))
+	if ttl <= 0 then
+		redis.call("PERSIST", tag)
+	elseif current == -2 or (current >= 0 and current < ttl) then
+		redis.call("PEXPIRE", tag, ttl)
+	end
+end
+return 0
+`)
+
+// flushTagsScript deletes the members of the tag sets KEYS, then the sets
+var flushTagsScript = redis.NewScript(`
+for _, tag in ipairs(KEYS) do
+	local keys = redis.call("SMEMBERS", tag)
+	for i = 1, #keys, 1000 do
+		redis.call("DEL", unpack(keys, i, math.min(i + 999, #keys)))
+	end
+	redis.call("DEL", tag)
+end
+return 0
+`)
 
 // RedisCacheDriver is a structure for managing caching using Redis.
 type RedisCacheDriver struct {
+	ctx    context.Context
 	client *redis.Client
 	cfg    *cache_models.RedisCacheConfig
+	codec  cache_codecs.Codec // Encodes the values of Set, nil to let Redis format them
+	mu     sync.RWMutex
 }
 
 // Name returns the name of the cache driver.
@@ -25,48 +59,125 @@ func (r *RedisCacheDriver) Name() string {
 	return "redis"
 }
 
+// SetContext binds the Redis commands to the given parent context.
+func (r *RedisCacheDriver) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// parentContext returns the parent context of the Redis commands.
+func (r *RedisCacheDriver) parentContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 // Init initializes the Redis cache driver with the provided configuration.
+// Calling Init again with a different configuration re-initializes the driver.
 func (r *RedisCacheDriver) Init(config interface{}) error {
-	if r.client != nil {
+	cfg, ok := config.(cache_models.RedisCacheConfig)
+
+	if !ok {
+		return errors.New("invalid redis cache configuration: expected a cache_models.RedisCacheConfig type")
+	}
+
+	r.mu.RLock()
+	unchanged := r.client != nil && *r.cfg == cfg
+	r.mu.RUnlock()
+
+	if unchanged {
 		return nil
 	}
 
+	return r.Reload(cfg)
+}
+
+// Reload connects to Redis with the new configuration and closes the previous client.
+func (r *RedisCacheDriver) Reload(config interface{}) error {
 	cfg, ok := config.(cache_models.RedisCacheConfig)
 
 	if !ok {
 		return errors.New("invalid redis cache configuration: expected a cache_models.RedisCacheConfig type")
 	}
 
-	r.cfg = &cfg
-	r.client = redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", r.cfg.Host, r.cfg.Port),
-		Username: r.cfg.Username,
-		Password: r.cfg.Password,
-		DB:       r.cfg.Database,
+	codec, err := newCodec(cfg.CodecConfig, false)
+	if err != nil {
+		return err
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.Database,
 	})
 
+	r.mu.Lock()
+	previous := r.client
+	r.cfg = &cfg
+	r.codec = codec
+	r.client = client
+	r.mu.Unlock()
+
+	if previous != nil {
+		return previous.Close()
+	}
+
 	return nil
 }
 
+// getClient returns the current Redis client.
+func (r *RedisCacheDriver) getClient() *redis.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.client
+}
+
+// Client returns the current Redis client, e.g. to run scripts.
+func (r *RedisCacheDriver) Client() *redis.Client {
+	return r.getClient()
+}
+
+// Codec returns the codec of the values, or nil if Redis formats them.
+func (r *RedisCacheDriver) Codec() cache_codecs.Codec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.codec
+}
+
 // Set stores data in Redis with an expiration time.
 func (r *RedisCacheDriver) Set(key string, value interface{}, expiration time.Duration) error {
-	return r.client.Set(ctx, key, value, expiration).Err()
+	if codec := r.Codec(); codec != nil {
+		encodedValue, err := encode(codec, value)
+		if err != nil {
+			return err
+		}
+		value = encodedValue
+	}
+
+	return r.getClient().Set(r.parentContext(), key, value, expiration).Err()
 }
 
 // Get retrieves data from Redis by key.
 func (r *RedisCacheDriver) Get(key string) (interface{}, error) {
-	val, err := r.client.Get(ctx, key).Result()
+	val, err := r.getClient().Get(r.parentContext(), key).Result()
 	if err == redis.Nil {
 		return nil, nil // Key does not exist.
 	} else if err != nil {
 		return nil, err
 	}
+
+	if codec := r.Codec(); codec != nil {
+		return decode(codec, []byte(val))
+	}
 	return val, nil
 }
 
 // Has checks if a key exists in Redis.
 func (r *RedisCacheDriver) Has(key string) (bool, error) {
-	exists, err := r.client.Exists(ctx, key).Result()
+	exists, err := r.getClient().Exists(r.parentContext(), key).Result()
 	if err != nil {
 		return false, err
 	}
@@ -76,5 +187,168 @@ func (r *RedisCacheDriver) Has(key string) (bool, error) {
 
 // Delete removes data from Redis by key.
 func (r *RedisCacheDriver) Delete(key string) error {
-	return r.client.Del(ctx, key).Err()
+	return r.getClient().Del(r.parentContext(), key).Err()
+}
+
+// Load returns the bytes stored under key.
+func (r *RedisCacheDriver) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.getClient().Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Store stores the bytes under key; a ttl of zero or less keeps them until deleted.
+func (r *RedisCacheDriver) Store(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return r.getClient().Set(ctx, key, value, ttl).Err()
+}
+
+// Exists checks if a key exists in Redis.
+func (r *RedisCacheDriver) Exists(ctx context.Context, key string) (bool, error) {
+	exists, err := r.getClient().Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return exists > 0, nil
+}
+
+// Remove removes data from Redis by key.
+func (r *RedisCacheDriver) Remove(ctx context.Context, key string) error {
+	return r.getClient().Del(ctx, key).Err()
+}
+
+// TTL returns the remaining time to live of key, NoExpiration if it does not expire.
+func (r *RedisCacheDriver) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ttl, err := r.getClient().PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch ttl {
+	case -2: // The key does not exist
+		return 0, false, nil
+	case -1: // The key does not expire
+		return cache_models.NoExpiration, true, nil
+	}
+	return ttl, true, nil
+}
+
+// LoadMany returns the bytes of the keys that exist with MGET.
+func (r *RedisCacheDriver) LoadMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	results, err := r.getClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if value, ok := result.(string); ok {
+			values[keys[i]] = []byte(value)
+		}
+	}
+
+	return values, nil
+}
+
+// StoreMany stores the bytes of every key in a single pipeline.
+func (r *RedisCacheDriver) StoreMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	_, err := r.getClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, key, value, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// RemoveMany removes the keys with a single DEL.
+func (r *RedisCacheDriver) RemoveMany(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.getClient().Del(ctx, keys...).Err()
+}
+
+// IncrementBy adds delta to the counter of key with INCRBY.
+func (r *RedisCacheDriver) IncrementBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return r.getClient().IncrBy(ctx, key, delta).Result()
+}
+
+// StoreIfAbsent stores the bytes unless key exists with SET NX, and reports whether they were stored.
+func (r *RedisCacheDriver) StoreIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return r.getClient().SetNX(ctx, key, value, ttl).Result()
+}
+
+// LoadAndRemove returns and removes the bytes of key with GETDEL.
+func (r *RedisCacheDriver) LoadAndRemove(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.getClient().GetDel(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// TagKeys adds the keys to the set of every tag.
+func (r *RedisCacheDriver) TagKeys(ctx context.Context, tags []string, keys []string, ttl time.Duration) error {
+	if len(tags) == 0 || len(keys) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, max(ttl, 0).Milliseconds())
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	return tagScript.Run(ctx, r.getClient(), redisTagKeys(tags), args...).Err()
+}
+
+// FlushTags removes the keys in the sets of the tags, and the sets, atomically.
+func (r *RedisCacheDriver) FlushTags(ctx context.Context, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return flushTagsScript.Run(ctx, r.getClient(), redisTagKeys(tags)).Err()
+}
+
+// redisTagKeys returns the keys of the sets of the tags
+func redisTagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = redisTagPrefix + tag
+	}
+	return keys
+}
+
+// Health pings the Redis server.
+func (r *RedisCacheDriver) Health(ctx context.Context) error {
+	return r.getClient().Ping(ctx).Err()
+}
+
+// Close closes the Redis client.
+func (r *RedisCacheDriver) Close() error {
+	client := r.getClient()
+	if client == nil {
+		return nil
+	}
+	return client.Close()
 }
@@ -1,19 +1,73 @@
 package cache_models
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
+// NoExpiration is the ttl reported for keys that do not expire
+const NoExpiration time.Duration = -1
+
+// CodecConfig selects how a driver encodes the values: codec is json, gob, msgpack or raw,
+// compression is gzip or zstd and applies to encoded values of at least compress_threshold bytes.
+// Without a codec the values are stored as they are. The gob codec keeps the Go types of the values
+// of the CacheDriver methods, which must be registered with gob.Register unless they are basic types.
+type CodecConfig struct {
+	Codec             string `mapstructure:"codec"`
+	Compression       string `mapstructure:"compression"`
+	CompressThreshold int    `mapstructure:"compress_threshold"`
+}
+
 type FileCacheConfig struct {
-	Path      string
-	Serialize bool
+	Path        string `mapstructure:"path" validate:"required"`
+	Serialize   bool   `mapstructure:"serialize"` // Deprecated: use codec json
+	CodecConfig `mapstructure:",squash"`
 }
 
+// Eviction policies of a bounded map cache
+const (
+	EvictLRU = "lru" // Least recently used first
+	EvictLFU = "lfu" // Least frequently used first, least recently used among equals
+	EvictTTL = "ttl" // Closest to expiring first, least recently used among the keys without expiration
+)
+
+// MapCacheConfig bounds the map cache with max_entries and max_bytes, zero for no limit; entries
+// are evicted by the eviction policy, lru by default. Every cleanup_interval the expired entries
+// are removed in the background, otherwise only when they are read.
 type MapCacheConfig struct {
-	Path      string
-	Serialize bool
+	Path            string        `mapstructure:"path"`
+	Serialize       bool          `mapstructure:"serialize"` // Deprecated: use codec json
+	MaxEntries      int           `mapstructure:"max_entries"`
+	MaxBytes        int64         `mapstructure:"max_bytes"` // Size of the keys and of the string or encoded values
+	Eviction        string        `mapstructure:"eviction"`
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+	CodecConfig     `mapstructure:",squash"`
+}
+
+// CacheStats counts the entries of a cache and the outcome of its reads and removals.
+type CacheStats struct {
+	Entries     int   // Entries stored, including the expired ones not removed yet
+	Bytes       int64 // Size of the entries, see MapCacheConfig.MaxBytes
+	Hits        int64 // Reads of a stored key
+	Misses      int64 // Reads of a missing or expired key
+	Evictions   int64 // Entries removed to respect the limits
+	Expirations int64 // Expired entries removed
 }
 
 type RedisCacheConfig struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	Database int
+	Host        string `mapstructure:"host" validate:"required"`
+	Port        string `mapstructure:"port"`
+	Username    string `mapstructure:"username"`
+	Password    string `mapstructure:"password"`
+	Database    int    `mapstructure:"database"`
+	CodecConfig `mapstructure:",squash"`
+}
+
+// String formats the configuration with the credentials redacted
+func (c RedisCacheConfig) String() string {
+	type plain RedisCacheConfig
+	c.Password = helpers.Redact(c.Password)
+	return fmt.Sprintf("%+v", plain(c))
 }
@@ -1,6 +1,15 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_codecs"
+	"github.com/HemendCo/go-core/cache/cache_models"
+)
+
+// NoExpiration is the ttl reported for keys that do not expire
+const NoExpiration = cache_models.NoExpiration
 
 type CacheDriver interface {
 	Name() string
@@ -10,3 +19,68 @@ type CacheDriver interface {
 	Has(key string) (bool, error)
 	Delete(key string) error
 }
+
+// StoreDriver is the context-aware API of a driver, storing encoded values as bytes; see Repository.
+// A ttl of zero or less keeps the value until it is deleted.
+type StoreDriver interface {
+	// Load returns the value of key and whether it exists and did not expire.
+	Load(ctx context.Context, key string) ([]byte, bool, error)
+	Store(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Remove(ctx context.Context, key string) error
+	// TTL returns the remaining time to live of key, NoExpiration if it does not expire, and whether it exists.
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+}
+
+// BulkDriver is implemented by drivers reading and writing several keys at once.
+type BulkDriver interface {
+	LoadMany(ctx context.Context, keys []string) (map[string][]byte, error)
+	StoreMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error
+	RemoveMany(ctx context.Context, keys []string) error
+}
+
+// AtomicStoreDriver is implemented by drivers updating a key atomically.
+type AtomicStoreDriver interface {
+	// IncrementBy adds delta to the counter of key, which starts at zero and keeps its ttl.
+	IncrementBy(ctx context.Context, key string, delta int64) (int64, error)
+	// StoreIfAbsent stores the value unless key exists, and reports whether it was stored.
+	StoreIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// LoadAndRemove returns and removes the value of key.
+	LoadAndRemove(ctx context.Context, key string) ([]byte, bool, error)
+}
+
+// TagDriver is implemented by drivers indexing keys by tag, to remove them together; see Repository.Tags.
+type TagDriver interface {
+	// TagKeys adds the keys to the index of every tag, kept at least as long as keys stored with the ttl.
+	TagKeys(ctx context.Context, tags []string, keys []string, ttl time.Duration) error
+	// FlushTags removes the keys indexed by any of the tags, and the indexes.
+	FlushTags(ctx context.Context, tags []string) error
+}
+
+// StatsDriver is implemented by drivers counting their entries, hits, misses and evictions.
+type StatsDriver interface {
+	Stats() cache_models.CacheStats
+}
+
+// CodecDriver is implemented by drivers whose values are encoded by a configured codec.
+type CodecDriver interface {
+	// Codec returns the codec of the driver, or nil if none is configured.
+	Codec() cache_codecs.Codec
+}
+
+// ContextAwareDriver is implemented by drivers whose blocking calls can be bound to a parent context.
+type ContextAwareDriver interface {
+	SetContext(ctx context.Context)
+}
+
+// ReloadableDriver is implemented by drivers that can be re-initialized with a new configuration.
+type ReloadableDriver interface {
+	Reload(config interface{}) error
+}
+
+// AtomicDriver is implemented by drivers that can update a value atomically within the process.
+type AtomicDriver interface {
+	// Modify replaces the value of key with the value returned by fn, which receives the current
+	// value or nil if the key does not exist or expired. A nil value deletes the key.
+	Modify(key string, fn func(value interface{}) (interface{}, time.Duration, error)) error
+}
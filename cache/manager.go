@@ -1,11 +1,13 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"github.com/HemendCo/go-core/cache/cache_drivers"
 )
 
 type CacheManager struct {
+	ctx     context.Context
 	drivers map[string]CacheDriver
 }
 
@@ -21,6 +23,13 @@ func NewCacheManager(drivers ...CacheDriver) *CacheManager {
 	return manager
 }
 
+// WithContext binds the blocking calls of the drivers created by the manager to ctx,
+// usually the root context of the application.
+func (dm *CacheManager) WithContext(ctx context.Context) *CacheManager {
+	dm.ctx = ctx
+	return dm
+}
+
 func (dm *CacheManager) RegisterDrivers(drivers ...CacheDriver) {
 	for _, driver := range drivers {
 		dm.drivers[driver.Name()] = driver
@@ -33,6 +42,10 @@ func (dm *CacheManager) CreateCacheFactory(driverName string, config interface{}
 		return nil, fmt.Errorf("unsupported cache driver %s", driverName)
 	}
 
+	if aware, ok := driver.(ContextAwareDriver); ok && dm.ctx != nil {
+		aware.SetContext(dm.ctx)
+	}
+
 	if err := driver.Init(config); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_models"
+)
+
+// contextDriver is a driver recording the context it is bound to
+type contextDriver struct {
+	ctx context.Context
+}
+
+func (d *contextDriver) Name() string                                             { return "context" }
+func (d *contextDriver) Init(config interface{}) error                            { return nil }
+func (d *contextDriver) Set(key string, value interface{}, _ time.Duration) error { return nil }
+func (d *contextDriver) Get(key string) (interface{}, error)                      { return nil, nil }
+func (d *contextDriver) Has(key string) (bool, error)                             { return false, nil }
+func (d *contextDriver) Delete(key string) error                                  { return nil }
+func (d *contextDriver) SetContext(ctx context.Context)                           { d.ctx = ctx }
+
+func TestCacheManagerBindsDriversToContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		manager func(driver CacheDriver) *CacheManager
+		want    context.Context
+	}{
+		{name: "with context", manager: func(driver CacheDriver) *CacheManager { return NewCacheManager(driver).WithContext(ctx) }, want: ctx},
+		{name: "without context", manager: func(driver CacheDriver) *CacheManager { return NewCacheManager(driver) }, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := &contextDriver{}
+			if _, err := tt.manager(driver).CreateCacheFactory("context", nil); err != nil {
+				t.Fatal(err)
+			}
+			if driver.ctx != tt.want {
+				t.Fatalf("driver bound to %v, want %v", driver.ctx, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisDriverStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The address is never dialed, the commands fail on the cancelled context first
+	driver, err := NewCacheManager().WithContext(ctx).CreateCacheFactory("redis", cache_models.RedisCacheConfig{Host: "127.0.0.1", Port: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Set("key", "value", time.Minute); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Set error = %v, want %v", err, context.Canceled)
+	}
+}
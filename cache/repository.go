@@ -0,0 +1,413 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_codecs"
+)
+
+type Option interface {
+}
+
+// option holds the settings of a Repository
+type option struct {
+	codec  cache_codecs.Codec
+	prefix string
+	tags   []string
+}
+
+// Internal option representations.
+type (
+	codecOption  struct{ codec cache_codecs.Codec }
+	prefixOption string
+	tagsOption   []string
+)
+
+// Codec returns an option to specify the codec of the values, by default the codec
+// configured for the driver or JSON.
+func Codec(codec cache_codecs.Codec) Option {
+	return codecOption{codec: codec}
+}
+
+// Prefix returns an option to prepend a prefix to every key of the repository.
+func Prefix(prefix string) Option {
+	return prefixOption(prefix)
+}
+
+// Tags returns an option to index every key written by the repository under the tags,
+// to remove them together with FlushTags.
+func Tags(tags ...string) Option {
+	return tagsOption(tags)
+}
+
+func composeOptions(codec cache_codecs.Codec, opts ...Option) option {
+	res := option{
+		codec: codec,
+	}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case codecOption:
+			if opt.codec != nil {
+				res.codec = opt.codec
+			}
+		case prefixOption:
+			res.prefix = string(opt)
+		case tagsOption:
+			res.tags = append(res.tags, opt...)
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
+
+// Repository is the context-aware cache API. Values are encoded by its codec, so they read
+// back the same from every driver. Keys written by a Repository should not be read with the
+// CacheDriver methods, and the other way around.
+type Repository struct {
+	driver StoreDriver
+	opt    option
+}
+
+// NewRepository creates a repository over a driver implementing StoreDriver.
+func NewRepository(driver CacheDriver, opts ...Option) (*Repository, error) {
+	store, ok := driver.(StoreDriver)
+	if !ok {
+		return nil, fmt.Errorf("cache driver %s does not support the context-aware API", driver.Name())
+	}
+
+	var codec cache_codecs.Codec = &cache_codecs.JSONCodec{}
+	if codecDriver, ok := driver.(CodecDriver); ok && codecDriver.Codec() != nil {
+		codec = codecDriver.Codec()
+	}
+
+	opt := composeOptions(codec, opts...)
+	if _, ok := driver.(TagDriver); len(opt.tags) > 0 && !ok {
+		return nil, fmt.Errorf("cache driver %s does not support tags", driver.Name())
+	}
+
+	return &Repository{
+		driver: store,
+		opt:    opt,
+	}, nil
+}
+
+// Driver returns the driver of the repository.
+func (r *Repository) Driver() StoreDriver {
+	return r.driver
+}
+
+// Codec returns the codec of the repository.
+func (r *Repository) Codec() cache_codecs.Codec {
+	return r.opt.codec
+}
+
+// Get decodes the value of key into target, a pointer, and reports whether the key was found.
+func (r *Repository) Get(ctx context.Context, key string, target interface{}) (bool, error) {
+	data, found, err := r.driver.Load(ctx, r.key(key))
+	if err != nil || !found {
+		return false, err
+	}
+
+	if err := r.opt.codec.Unmarshal(data, target); err != nil {
+		return false, fmt.Errorf("failed to decode cache key %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set encodes and stores the value of key; a ttl of zero or less keeps it until deleted.
+func (r *Repository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := r.opt.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache key %s: %w", key, err)
+	}
+
+	if err := r.driver.Store(ctx, r.key(key), data, ttl); err != nil {
+		return err
+	}
+	return r.index(ctx, ttl, r.key(key))
+}
+
+// Has reports whether key exists and did not expire.
+func (r *Repository) Has(ctx context.Context, key string) (bool, error) {
+	return r.driver.Exists(ctx, r.key(key))
+}
+
+// Delete removes key.
+func (r *Repository) Delete(ctx context.Context, key string) error {
+	return r.driver.Remove(ctx, r.key(key))
+}
+
+// Forever encodes and stores the value of key until it is deleted.
+func (r *Repository) Forever(ctx context.Context, key string, value interface{}) error {
+	return r.Set(ctx, key, value, 0)
+}
+
+// Add stores the value of key only if the key does not exist, and reports whether it was stored.
+func (r *Repository) Add(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	atomic, err := r.atomic()
+	if err != nil {
+		return false, err
+	}
+
+	data, err := r.opt.codec.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode cache key %s: %w", key, err)
+	}
+
+	stored, err := atomic.StoreIfAbsent(ctx, r.key(key), data, ttl)
+	if err != nil || !stored {
+		return false, err
+	}
+	return true, r.index(ctx, ttl, r.key(key))
+}
+
+// Increment atomically adds by to the counter of key and returns the new value. A missing key
+// starts at zero and keeps no expiration; an existing key keeps its ttl. Counters are stored as
+// decimal text whatever the codec, so read them with Increment(ctx, key, 0).
+func (r *Repository) Increment(ctx context.Context, key string, by int64) (int64, error) {
+	atomic, err := r.atomic()
+	if err != nil {
+		return 0, err
+	}
+	counter, err := atomic.IncrementBy(ctx, r.key(key), by)
+	if err != nil {
+		return 0, err
+	}
+	return counter, r.index(ctx, 0, r.key(key))
+}
+
+// Decrement atomically subtracts by from the counter of key, see Increment.
+func (r *Repository) Decrement(ctx context.Context, key string, by int64) (int64, error) {
+	return r.Increment(ctx, key, -by)
+}
+
+// TTL returns the remaining time to live of key, NoExpiration if it does not expire,
+// and whether the key exists.
+func (r *Repository) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return r.driver.TTL(ctx, r.key(key))
+}
+
+// SetMany encodes and stores several values with the same ttl.
+func (r *Repository) SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := r.opt.codec.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode cache key %s: %w", key, err)
+		}
+		encoded[r.key(key)] = data
+	}
+
+	if bulk, ok := r.driver.(BulkDriver); ok {
+		if err := bulk.StoreMany(ctx, encoded, ttl); err != nil {
+			return err
+		}
+	} else {
+		for key, data := range encoded {
+			if err := r.driver.Store(ctx, key, data, ttl); err != nil {
+				return err
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(encoded))
+	for key := range encoded {
+		keys = append(keys, key)
+	}
+	return r.index(ctx, ttl, keys...)
+}
+
+// DeleteMany removes several keys.
+func (r *Repository) DeleteMany(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	stored := make([]string, len(keys))
+	for i, key := range keys {
+		stored[i] = r.key(key)
+	}
+
+	if bulk, ok := r.driver.(BulkDriver); ok {
+		return bulk.RemoveMany(ctx, stored)
+	}
+
+	for _, key := range stored {
+		if err := r.driver.Remove(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tags returns a copy of the repository indexing the keys it writes under the tags, in addition
+// to its own tags, e.g. repository.Tags("user:42", "tenant:7").Set(ctx, key, value, ttl).
+// Reads are not affected: a tagged key is read by its key alone.
+func (r *Repository) Tags(tags ...string) *Repository {
+	tagged := *r
+	tagged.opt.tags = append(append([]string(nil), r.opt.tags...), tags...)
+	return &tagged
+}
+
+// Flush removes the keys indexed under the tags of the repository.
+func (r *Repository) Flush(ctx context.Context) error {
+	return r.FlushTags(ctx, r.opt.tags...)
+}
+
+// FlushTags removes the keys indexed under any of the tags.
+func (r *Repository) FlushTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	tagger, err := r.tagger()
+	if err != nil {
+		return err
+	}
+	return tagger.FlushTags(ctx, r.tagNames(tags))
+}
+
+// index adds the keys to the indexes of the tags of the repository, kept as long as the ttl
+func (r *Repository) index(ctx context.Context, ttl time.Duration, keys ...string) error {
+	if len(r.opt.tags) == 0 || len(keys) == 0 {
+		return nil
+	}
+
+	tagger, err := r.tagger()
+	if err != nil {
+		return err
+	}
+	return tagger.TagKeys(ctx, r.tagNames(r.opt.tags), keys, ttl)
+}
+
+// tagger returns the driver as a TagDriver
+func (r *Repository) tagger() (TagDriver, error) {
+	tagger, ok := r.driver.(TagDriver)
+	if !ok {
+		return nil, fmt.Errorf("cache driver does not support tags")
+	}
+	return tagger, nil
+}
+
+// tagNames returns the tags stored in the driver, prefixed like the keys
+func (r *Repository) tagNames(tags []string) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = r.opt.prefix + tag
+	}
+	return names
+}
+
+// loadMany returns the encoded values of the keys found, by key
+func (r *Repository) loadMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	res := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return res, nil
+	}
+
+	if bulk, ok := r.driver.(BulkDriver); ok {
+		stored := make([]string, len(keys))
+		for i, key := range keys {
+			stored[i] = r.key(key)
+		}
+
+		values, err := bulk.LoadMany(ctx, stored)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			if data, found := values[stored[i]]; found {
+				res[key] = data
+			}
+		}
+		return res, nil
+	}
+
+	for _, key := range keys {
+		data, found, err := r.driver.Load(ctx, r.key(key))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			res[key] = data
+		}
+	}
+	return res, nil
+}
+
+// atomic returns the driver as an AtomicStoreDriver
+func (r *Repository) atomic() (AtomicStoreDriver, error) {
+	atomic, ok := r.driver.(AtomicStoreDriver)
+	if !ok {
+		return nil, fmt.Errorf("cache driver does not support atomic operations")
+	}
+	return atomic, nil
+}
+
+// key returns the key stored in the driver
+func (r *Repository) key(key string) string {
+	return r.opt.prefix + key
+}
+
+// GetAs returns the value of key decoded as T, and whether the key was found.
+func GetAs[T any](ctx context.Context, repository *Repository, key string) (T, bool, error) {
+	var value T
+	found, err := repository.Get(ctx, key, &value)
+	return value, found, err
+}
+
+// Remember returns the value of key decoded as T or, if the key is missing, stores and
+// returns the value computed by fn.
+func Remember[T any](ctx context.Context, repository *Repository, key string, ttl time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	value, found, err := GetAs[T](ctx, repository, key)
+	if err != nil || found {
+		return value, err
+	}
+
+	if value, err = fn(ctx); err != nil {
+		return value, err
+	}
+
+	return value, repository.Set(ctx, key, value, ttl)
+}
+
+// GetMany returns the values of the keys found, decoded as T, by key.
+func GetMany[T any](ctx context.Context, repository *Repository, keys ...string) (map[string]T, error) {
+	values, err := repository.loadMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]T, len(values))
+	for key, data := range values {
+		var value T
+		if err := repository.opt.codec.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode cache key %s: %w", key, err)
+		}
+		res[key] = value
+	}
+	return res, nil
+}
+
+// Pull returns the value of key decoded as T and deletes it, and whether the key was found.
+func Pull[T any](ctx context.Context, repository *Repository, key string) (T, bool, error) {
+	var value T
+
+	atomic, err := repository.atomic()
+	if err != nil {
+		return value, false, err
+	}
+
+	data, found, err := atomic.LoadAndRemove(ctx, repository.key(key))
+	if err != nil || !found {
+		return value, false, err
+	}
+
+	if err := repository.opt.codec.Unmarshal(data, &value); err != nil {
+		return value, false, fmt.Errorf("failed to decode cache key %s: %w", key, err)
+	}
+	return value, true, nil
+}
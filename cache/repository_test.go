@@ -0,0 +1,494 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/cache/cache_codecs"
+	"github.com/HemendCo/go-core/cache/cache_models"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// user is a typed value of the tests
+type user struct {
+	ID   int    `json:"id" msgpack:"id"`
+	Name string `json:"name" msgpack:"name"`
+}
+
+// driverCase creates a cache driver and lets its keys expire
+type driverCase struct {
+	name    string
+	driver  CacheDriver
+	advance func(d time.Duration) // Moves the clock of the driver forward
+}
+
+// driverCases returns a map, a file and a redis driver encoding their values with the codec
+func driverCases(t *testing.T, codec cache_models.CodecConfig) []driverCase {
+	t.Helper()
+
+	wd, _ := os.Getwd()
+	path, err := filepath.Rel(wd, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := miniredis.RunT(t)
+
+	configs := []struct {
+		name    string
+		config  interface{}
+		advance func(d time.Duration)
+	}{
+		{name: "map", config: cache_models.MapCacheConfig{CodecConfig: codec}, advance: time.Sleep},
+		{name: "file", config: cache_models.FileCacheConfig{Path: path, CodecConfig: codec}, advance: time.Sleep},
+		{name: "redis", config: cache_models.RedisCacheConfig{Host: server.Host(), Port: server.Port(), CodecConfig: codec}, advance: server.FastForward},
+	}
+
+	cases := make([]driverCase, len(configs))
+	for i, config := range configs {
+		driver, err := NewCacheManager().CreateCacheFactory(config.name, config.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if closer, ok := driver.(interface{ Close() error }); ok {
+			t.Cleanup(func() { closer.Close() })
+		}
+		cases[i] = driverCase{name: config.name, driver: driver, advance: config.advance}
+	}
+	return cases
+}
+
+// newRepository creates a repository over the driver
+func newRepository(t *testing.T, driver CacheDriver, opts ...Option) *Repository {
+	t.Helper()
+
+	repository, err := NewRepository(driver, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repository
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+
+	for _, codec := range []string{"", "json", "gob", "msgpack"} {
+		for _, dc := range driverCases(t, cache_models.CodecConfig{Codec: codec}) {
+			t.Run(dc.name+"/"+codec, func(t *testing.T) {
+				repository := newRepository(t, dc.driver)
+				alice := user{ID: 1, Name: "alice"}
+
+				if err := repository.Set(ctx, "user:1", alice, time.Minute); err != nil {
+					t.Fatal(err)
+				}
+				if err := repository.Forever(ctx, "user:2", user{ID: 2, Name: "bob"}); err != nil {
+					t.Fatal(err)
+				}
+
+				tests := []struct {
+					key        string
+					want       user
+					wantFound  bool
+					wantTTL    time.Duration // Upper bound of the ttl, NoExpiration if the key does not expire
+					minimumTTL time.Duration
+				}{
+					{key: "user:1", want: alice, wantFound: true, wantTTL: time.Minute, minimumTTL: 50 * time.Second},
+					{key: "user:2", want: user{ID: 2, Name: "bob"}, wantFound: true, wantTTL: NoExpiration, minimumTTL: NoExpiration},
+					{key: "user:3"},
+				}
+				for _, tt := range tests {
+					got, found, err := GetAs[user](ctx, repository, tt.key)
+					if err != nil || found != tt.wantFound || got != tt.want {
+						t.Fatalf("GetAs %s = %+v, %v, %v; want %+v, %v", tt.key, got, found, err, tt.want, tt.wantFound)
+					}
+
+					if has, err := repository.Has(ctx, tt.key); err != nil || has != tt.wantFound {
+						t.Fatalf("Has %s = %v, %v; want %v", tt.key, has, err, tt.wantFound)
+					}
+
+					ttl, exists, err := repository.TTL(ctx, tt.key)
+					if err != nil || exists != tt.wantFound {
+						t.Fatalf("TTL %s exists = %v, %v; want %v", tt.key, exists, err, tt.wantFound)
+					}
+					if exists && (ttl > tt.wantTTL || ttl < tt.minimumTTL) {
+						t.Fatalf("TTL %s = %v, want up to %v", tt.key, ttl, tt.wantTTL)
+					}
+				}
+
+				if err := repository.Delete(ctx, "user:1"); err != nil {
+					t.Fatal(err)
+				}
+				if found, err := repository.Get(ctx, "user:1", &user{}); err != nil || found {
+					t.Fatalf("Get after Delete = %v, %v; want not found", found, err)
+				}
+			})
+		}
+	}
+}
+
+func TestRepositoryExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver)
+			if err := repository.Set(ctx, "session", "token", time.Second); err != nil {
+				t.Fatal(err)
+			}
+
+			dc.advance(1100 * time.Millisecond)
+
+			if found, err := repository.Get(ctx, "session", new(string)); err != nil || found {
+				t.Fatalf("Get of an expired key = %v, %v; want not found", found, err)
+			}
+			if _, exists, err := repository.TTL(ctx, "session"); err != nil || exists {
+				t.Fatalf("TTL of an expired key exists = %v, %v", exists, err)
+			}
+		})
+	}
+}
+
+func TestRepositoryOptions(t *testing.T) {
+	ctx := context.Background()
+	driver := driverCases(t, cache_models.CodecConfig{Codec: "msgpack"})[0].driver
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantCodec string
+		wantKey   string // Key stored in the driver
+	}{
+		{name: "driver codec", wantCodec: "msgpack", wantKey: "key"},
+		{name: "codec", opts: []Option{Codec(&cache_codecs.GobCodec{})}, wantCodec: "gob", wantKey: "key"},
+		{name: "prefix", opts: []Option{Prefix("app:")}, wantCodec: "msgpack", wantKey: "app:key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newRepository(t, driver, tt.opts...)
+			if repository.Codec().Name() != tt.wantCodec {
+				t.Fatalf("codec %s, want %s", repository.Codec().Name(), tt.wantCodec)
+			}
+
+			if err := repository.Set(ctx, "key", "value", time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			defer repository.Delete(ctx, "key")
+
+			if exists, err := repository.Driver().Exists(ctx, tt.wantKey); err != nil || !exists {
+				t.Fatalf("key %s stored = %v, %v", tt.wantKey, exists, err)
+			}
+		})
+	}
+
+	// Without a codec configured for the driver, values are encoded as JSON
+	plain := driverCases(t, cache_models.CodecConfig{})[0].driver
+	if name := newRepository(t, plain).Codec().Name(); name != "json" {
+		t.Fatalf("default codec %s, want json", name)
+	}
+}
+
+// legacyDriver implements only the untyped API
+type legacyDriver struct{}
+
+func (d *legacyDriver) Name() string                                                      { return "legacy" }
+func (d *legacyDriver) Init(config interface{}) error                                     { return nil }
+func (d *legacyDriver) Set(key string, value interface{}, expiration time.Duration) error { return nil }
+func (d *legacyDriver) Get(key string) (interface{}, error)                               { return nil, nil }
+func (d *legacyDriver) Has(key string) (bool, error)                                      { return false, nil }
+func (d *legacyDriver) Delete(key string) error                                           { return nil }
+
+func TestNewRepositoryRequiresStoreDriver(t *testing.T) {
+	if _, err := NewRepository(&legacyDriver{}); err == nil {
+		t.Fatal("NewRepository over a driver without the context-aware API did not fail")
+	}
+}
+
+func TestRemember(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver)
+
+			calls := 0
+			compute := func(value user, err error) func(ctx context.Context) (user, error) {
+				return func(ctx context.Context) (user, error) {
+					calls++
+					return value, err
+				}
+			}
+
+			tests := []struct {
+				name      string
+				fn        func(ctx context.Context) (user, error)
+				want      user
+				wantErr   error
+				wantCalls int
+			}{
+				{name: "failure is not stored", fn: compute(user{}, errFailed), wantErr: errFailed, wantCalls: 1},
+				{name: "missing key", fn: compute(user{ID: 1}, nil), want: user{ID: 1}, wantCalls: 2},
+				{name: "stored key", fn: compute(user{ID: 2}, nil), want: user{ID: 1}, wantCalls: 2},
+			}
+			for _, tt := range tests {
+				got, err := Remember(ctx, repository, "remembered", time.Minute, tt.fn)
+				if !errors.Is(err, tt.wantErr) || got != tt.want || calls != tt.wantCalls {
+					t.Fatalf("%s: %+v, %v after %d calls; want %+v, %v after %d", tt.name, got, err, calls, tt.want, tt.wantErr, tt.wantCalls)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDecodeError(t *testing.T) {
+	ctx := context.Background()
+	repository := newRepository(t, driverCases(t, cache_models.CodecConfig{})[0].driver)
+
+	if err := repository.Set(ctx, "key", "text", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := GetAs[user](ctx, repository, "key"); err == nil {
+		t.Fatal("GetAs of a value of another type did not fail")
+	}
+}
+
+func TestBulkOperations(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{Codec: "msgpack"}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver, Prefix("bulk:"))
+
+			values := map[string]interface{}{"a": user{ID: 1}, "b": user{ID: 2}, "c": user{ID: 3}}
+			if err := repository.SetMany(ctx, values, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			if err := repository.DeleteMany(ctx, "b"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := GetMany[user](ctx, repository, "a", "b", "c", "missing")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := map[string]user{"a": {ID: 1}, "c": {ID: 3}}
+			if len(got) != len(want) {
+				t.Fatalf("GetMany = %v, want %v", got, want)
+			}
+			for key, value := range want {
+				if got[key] != value {
+					t.Fatalf("GetMany = %v, want %v", got, want)
+				}
+			}
+
+			if ttl, exists, err := repository.TTL(ctx, "a"); err != nil || !exists || ttl <= 50*time.Second {
+				t.Fatalf("TTL of a key set by SetMany = %v, %v, %v", ttl, exists, err)
+			}
+			if err := repository.DeleteMany(ctx); err != nil {
+				t.Fatalf("DeleteMany of no keys: %v", err)
+			}
+		})
+	}
+}
+
+func TestAtomicOperations(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver)
+
+			tests := []struct {
+				name    string
+				run     func() (interface{}, error)
+				want    interface{}
+				wantErr bool
+			}{
+				{name: "add a missing key", run: func() (interface{}, error) { return repository.Add(ctx, "lock", "first", time.Minute) }, want: true},
+				{name: "add an existing key", run: func() (interface{}, error) { return repository.Add(ctx, "lock", "second", time.Minute) }, want: false},
+				{name: "existing value kept", run: func() (interface{}, error) { v, _, err := GetAs[string](ctx, repository, "lock"); return v, err }, want: "first"},
+				{name: "pull", run: func() (interface{}, error) { v, _, err := Pull[string](ctx, repository, "lock"); return v, err }, want: "first"},
+				{name: "pulled key removed", run: func() (interface{}, error) { return repository.Has(ctx, "lock") }, want: false},
+				{name: "pull a missing key", run: func() (interface{}, error) { _, found, err := Pull[string](ctx, repository, "lock"); return found, err }, want: false},
+				{name: "increment a missing key", run: func() (interface{}, error) { return repository.Increment(ctx, "hits", 5) }, want: int64(5)},
+				{name: "decrement", run: func() (interface{}, error) { return repository.Decrement(ctx, "hits", 2) }, want: int64(3)},
+				{name: "read a counter", run: func() (interface{}, error) { return repository.Increment(ctx, "hits", 0) }, want: int64(3)},
+				{name: "counter keeps no expiration", run: func() (interface{}, error) { ttl, _, err := repository.TTL(ctx, "hits"); return ttl, err }, want: NoExpiration},
+				{name: "set before incrementing", run: func() (interface{}, error) { return nil, repository.Set(ctx, "text", "value", time.Minute) }},
+				{name: "increment a value that is not a counter", run: func() (interface{}, error) { return repository.Increment(ctx, "text", 1) }, wantErr: true},
+			}
+			for _, tt := range tests {
+				got, err := tt.run()
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+				}
+				if !tt.wantErr && got != tt.want {
+					t.Fatalf("%s = %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIncrementKeepsTTL(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver)
+			if _, err := repository.Add(ctx, "window", 0, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := repository.Increment(ctx, "window", 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if ttl, exists, err := repository.TTL(ctx, "window"); err != nil || !exists || ttl <= 50*time.Second || ttl > time.Minute {
+				t.Fatalf("TTL after Increment = %v, %v, %v; want about a minute", ttl, exists, err)
+			}
+		})
+	}
+}
+
+func TestAtomicOperationsAreExclusive(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver)
+
+			var wg sync.WaitGroup
+			var added atomic.Int32
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for j := 0; j < 25; j++ {
+						if _, err := repository.Increment(ctx, "counter", 1); err != nil {
+							t.Error(err)
+							return
+						}
+					}
+					if stored, err := repository.Add(ctx, "once", i, time.Minute); err != nil {
+						t.Error(err)
+					} else if stored {
+						added.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if counter, err := repository.Increment(ctx, "counter", 0); err != nil || counter != 200 {
+				t.Fatalf("counter = %d, %v; want 200", counter, err)
+			}
+			if added.Load() != 1 {
+				t.Fatalf("Add stored %d values, want 1", added.Load())
+			}
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			repository := newRepository(t, dc.driver, Prefix("app:"))
+			users := repository.Tags("users")
+			alice := users.Tags("user:1")
+
+			writes := []func() error{
+				func() error { return alice.Set(ctx, "profile:1", "alice", time.Minute) },
+				func() error { _, err := alice.Increment(ctx, "visits:1", 1); return err },
+				func() error { return users.Set(ctx, "profile:2", "bob", time.Minute) },
+				func() error { _, err := users.Add(ctx, "invite:2", "code", time.Minute); return err },
+				func() error {
+					return repository.Tags("posts").SetMany(ctx, map[string]interface{}{"post:1": "a", "post:2": "b"}, 0)
+				},
+				func() error { return repository.Set(ctx, "untagged", "kept", time.Minute) },
+			}
+			for _, write := range writes {
+				if err := write(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Another prefix keeps its own tags
+			other := newRepository(t, dc.driver, Prefix("other:"), Tags("users"))
+			if err := other.Set(ctx, "profile:1", "carol", time.Minute); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				name  string
+				flush func() error
+				want  map[string]bool // Keys of the repository and whether they still exist
+			}{
+				{
+					name:  "flush a user",
+					flush: func() error { return alice.FlushTags(ctx, "user:1") },
+					want:  map[string]bool{"profile:1": false, "visits:1": false, "profile:2": true, "invite:2": true, "post:1": true},
+				},
+				{
+					name:  "flush the tags of a repository",
+					flush: func() error { return users.Flush(ctx) },
+					want:  map[string]bool{"profile:2": false, "invite:2": false, "post:1": true, "untagged": true},
+				},
+				{
+					name:  "flush several tags",
+					flush: func() error { return repository.FlushTags(ctx, "posts", "missing") },
+					want:  map[string]bool{"post:1": false, "post:2": false, "untagged": true},
+				},
+				{
+					name:  "flush no tags",
+					flush: func() error { return repository.Flush(ctx) },
+					want:  map[string]bool{"untagged": true},
+				},
+			}
+			for _, tt := range tests {
+				if err := tt.flush(); err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				}
+				for key, want := range tt.want {
+					if exists, err := repository.Has(ctx, key); err != nil || exists != want {
+						t.Fatalf("%s: key %s exists = %v, %v; want %v", tt.name, key, exists, err, want)
+					}
+				}
+			}
+
+			if value, found, err := GetAs[string](ctx, other, "profile:1"); err != nil || !found || value != "carol" {
+				t.Fatalf("key of another prefix = %q, %v, %v; want it kept", value, found, err)
+			}
+		})
+	}
+}
+
+func TestTagsAreReindexedAfterFlush(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t, cache_models.CodecConfig{}) {
+		t.Run(dc.name, func(t *testing.T) {
+			tagged := newRepository(t, dc.driver, Tags("group"))
+
+			for round := 0; round < 2; round++ {
+				if err := tagged.Set(ctx, "key", round, time.Minute); err != nil {
+					t.Fatal(err)
+				}
+				if err := tagged.Flush(ctx); err != nil {
+					t.Fatal(err)
+				}
+				if exists, err := tagged.Has(ctx, "key"); err != nil || exists {
+					t.Fatalf("round %d: key exists after Flush = %v, %v", round, exists, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Bind decodes the section at key into target, a pointer to a struct or map, using the
+// `mapstructure` tags of the target, then checks the fields tagged `validate:"required"`.
+// An empty key binds the whole configuration.
+func (c *Config) Bind(key string, target interface{}) error {
+	var section interface{}
+	if key == "" {
+		section = c.AllSettings()
+	} else {
+		section = c.Get(key)
+	}
+
+	return decode(key, section, target)
+}
+
+// decode binds a configuration value into target and validates it
+func decode(key string, section interface{}, target interface{}) error {
+	if section == nil {
+		section = map[string]interface{}{}
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           target,
+		TagName:          "mapstructure",
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create decoder for config section '%s': %w", key, err)
+	}
+
+	if err := decoder.Decode(section); err != nil {
+		return fmt.Errorf("failed to bind config section '%s': %w", key, err)
+	}
+
+	if err := Validate(target); err != nil {
+		return fmt.Errorf("invalid config section '%s': %w", key, err)
+	}
+
+	return nil
+}
+
+// BindAs decodes the section at key into a new T, see Config.Bind.
+func BindAs[T any](c *Config, key string) (T, error) {
+	var target T
+	err := c.Bind(key, &target)
+	return target, err
+}
+
+// Require registers a section to be bound and validated by ValidateRequired,
+// typically at application startup.
+func (c *Config) Require(key string, target interface{}) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.required[key] = append(c.required[key], target)
+	return c
+}
+
+// ValidateRequired binds every required section and reports all the failures at once.
+func (c *Config) ValidateRequired() error {
+	c.mu.RLock()
+	required := make(map[string][]interface{}, len(c.required))
+	for key, targets := range c.required {
+		required[key] = targets
+	}
+	c.mu.RUnlock()
+
+	var errs []error
+	for key, targets := range required {
+		if !c.Has(key) {
+			errs = append(errs, fmt.Errorf("missing required config section '%s'", key))
+			continue
+		}
+
+		for _, target := range targets {
+			if err := c.Bind(key, target); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the fields of a struct tagged `validate:"required"` are not zero.
+// Nested structs, maps and slices of structs are checked recursively.
+func Validate(target interface{}) error {
+	return validateValue(reflect.ValueOf(target), "")
+}
+
+func validateValue(value reflect.Value, path string) error {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	var errs []error
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := fieldName(field)
+			if path != "" {
+				name = path + KeyDelimiter + name
+			}
+
+			if hasRule(field.Tag.Get("validate"), "required") && value.Field(i).IsZero() {
+				errs = append(errs, fmt.Errorf("field '%s' is required", name))
+				continue
+			}
+
+			if err := validateValue(value.Field(i), name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := validateValue(iter.Value(), fmt.Sprintf("%s%s%v", path, KeyDelimiter, iter.Key())); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fieldName returns the configuration name of a struct field
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// hasRule reports whether a comma separated validate tag contains the rule
+func hasRule(tag string, rule string) bool {
+	for _, item := range strings.Split(tag, ",") {
+		if strings.TrimSpace(item) == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// KeyDelimiter separates the sections of a configuration key, e.g. "database.connections.main"
+const KeyDelimiter = "."
+
+// Extensions lists the supported configuration file extensions in loading order
+var Extensions = []string{"yaml", "yml", "json", "toml"}
+
+type Option interface {
+}
+
+// option holds the settings of a Config
+type option struct {
+	path      string
+	name      string
+	profile   string
+	envPrefix string
+	files     []string
+	defaults  map[string]interface{}
+}
+
+// Internal option representations.
+type (
+	pathOption      string
+	nameOption      string
+	profileOption   string
+	envPrefixOption string
+	filesOption     []string
+	defaultsOption  map[string]interface{}
+)
+
+// Path returns an option to specify the directory the configuration files are loaded from.
+func Path(path string) Option {
+	return pathOption(path)
+}
+
+// Name returns an option to specify the base name of the configuration files, "config" by default.
+func Name(name string) Option {
+	return nameOption(name)
+}
+
+// Profile returns an option to specify the profile (e.g. dev, staging, prod) whose
+// files "<name>.<profile>.<ext>" are merged over the base files.
+// By default the profile is read from the <PREFIX>_ENV environment variable.
+func Profile(profile string) Option {
+	return profileOption(profile)
+}
+
+// EnvPrefix returns an option to specify the prefix of the environment variables
+// overriding configuration values, "APP" by default. APP_CACHE__DRIVER overrides cache.driver.
+func EnvPrefix(prefix string) Option {
+	return envPrefixOption(prefix)
+}
+
+// Files returns an option to load additional files after the base and profile files.
+func Files(paths ...string) Option {
+	return filesOption(paths)
+}
+
+// Defaults returns an option to specify default values, keyed by dotted keys or nested maps.
+func Defaults(values map[string]interface{}) Option {
+	return defaultsOption(values)
+}
+
+func composeOptions(opts ...Option) option {
+	res := option{
+		path:      ".",
+		name:      "config",
+		envPrefix: "APP",
+	}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case pathOption:
+			res.path = string(opt)
+		case nameOption:
+			res.name = string(opt)
+		case profileOption:
+			res.profile = string(opt)
+		case envPrefixOption:
+			res.envPrefix = string(opt)
+		case filesOption:
+			res.files = append(res.files, opt...)
+		case defaultsOption:
+			res.defaults = opt
+		default:
+			// ignore unexpected option
+		}
+	}
+	if res.profile == "" && res.envPrefix != "" {
+		res.profile = os.Getenv(res.envPrefix + "_ENV")
+	}
+	return res
+}
+
+// Config holds configuration values loaded from files and environment variables
+type Config struct {
+	mu        sync.RWMutex
+	opt       option
+	values    map[string]interface{}
+	overrides map[string]interface{}   // Values set by Set, kept over every source on reload
+	files     []string                 // Files loaded by the last Load
+	required  map[string][]interface{} // Sections bound and validated by ValidateRequired
+
+	resolver   SecretResolver  // Resolves the secret references, see UseSecrets
+	secretKeys map[string]bool // Keys whose values were resolved from secret references
+
+	subscriptions    []subscription // Change listeners notified by Reload
+	lastSubscription int
+}
+
+// New creates a Config; call Load to read its sources.
+func New(opts ...Option) *Config {
+	return &Config{
+		opt:        composeOptions(opts...),
+		values:     make(map[string]interface{}),
+		overrides:  make(map[string]interface{}),
+		required:   make(map[string][]interface{}),
+		secretKeys: make(map[string]bool),
+	}
+}
+
+// Load reads, in order of precedence from lowest to highest, the defaults, the base files,
+// the profile files, the additional files and the environment variables; values set by Set
+// override them all.
+// Secret references are resolved once a resolver is set, see UseSecrets.
+func (c *Config) Load() error {
+	values, files, secretKeys, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.values = values
+	c.files = files
+	c.secretKeys = secretKeys
+	c.mu.Unlock()
+
+	return nil
+}
+
+// read builds the configuration values from all sources and resolves their secret references
+func (c *Config) read() (map[string]interface{}, []string, map[string]bool, error) {
+	values := make(map[string]interface{})
+	var files []string
+
+	for key, value := range c.opt.defaults {
+		setPath(values, splitKey(key), normalize(value))
+	}
+
+	candidates := c.candidateFiles()
+	for _, file := range candidates {
+		if _, err := os.Stat(file); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, nil, nil, fmt.Errorf("error accessing config file '%s': %w", file, err)
+		}
+
+		content, err := readFile(file)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		merge(values, content)
+		files = append(files, file)
+	}
+
+	for key, value := range environment(c.opt.envPrefix) {
+		setPath(values, splitKey(key), value)
+	}
+
+	c.mu.RLock()
+	merge(values, copyMap(c.overrides))
+	c.mu.RUnlock()
+
+	values, secretKeys, err := resolveSecrets(values, c.secretResolver())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return values, files, secretKeys, nil
+}
+
+// candidateFiles returns the configuration files to look for, in loading order
+func (c *Config) candidateFiles() []string {
+	var files []string
+
+	for _, ext := range Extensions {
+		files = append(files, filepath.Join(c.opt.path, fmt.Sprintf("%s.%s", c.opt.name, ext)))
+	}
+
+	if c.opt.profile != "" {
+		for _, ext := range Extensions {
+			files = append(files, filepath.Join(c.opt.path, fmt.Sprintf("%s.%s.%s", c.opt.name, c.opt.profile, ext)))
+		}
+	}
+
+	for _, file := range c.opt.files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(c.opt.path, file)
+		}
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// Profile returns the active profile
+func (c *Config) Profile() string {
+	return c.opt.profile
+}
+
+// Files returns the configuration files read by the last Load
+func (c *Config) Files() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.files...)
+}
+
+// Get returns the value for a dotted key, or nil if it is not set
+func (c *Config) Get(key string) interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, _ := lookup(c.values, splitKey(key))
+	return value
+}
+
+// Has reports whether a value is set for the key
+func (c *Config) Has(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := lookup(c.values, splitKey(key))
+	return ok
+}
+
+// Set overrides the value for a dotted key. The override is kept when the configuration is
+// reloaded, and the subscribers of the changed sections are notified.
+func (c *Config) Set(key string, value interface{}) {
+	value = normalize(value)
+
+	c.mu.Lock()
+	// The values are replaced rather than modified, so the previous values stay intact
+	previous := c.values
+	current := copyMap(previous)
+	setPath(current, splitKey(key), copyValue(value))
+	setPath(c.overrides, splitKey(key), copyValue(value))
+	c.values = current
+	subscriptions := append([]subscription(nil), c.subscriptions...)
+	c.mu.Unlock()
+
+	notify(subscriptions, previous, current)
+}
+
+// GetString returns the value for the key as a string
+func (c *Config) GetString(key string) string {
+	return cast.ToString(c.Get(key))
+}
+
+// GetInt returns the value for the key as an int
+func (c *Config) GetInt(key string) int {
+	return cast.ToInt(c.Get(key))
+}
+
+// GetBool returns the value for the key as a bool
+func (c *Config) GetBool(key string) bool {
+	return cast.ToBool(c.Get(key))
+}
+
+// GetDuration returns the value for the key as a time.Duration, e.g. "1m30s"
+func (c *Config) GetDuration(key string) time.Duration {
+	return cast.ToDuration(c.Get(key))
+}
+
+// Keys returns the keys of a section, or nil if the key is not a section
+func (c *Config) Keys(key string) []string {
+	section, ok := c.Get(key).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(section))
+	for name := range section {
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+// AllSettings returns a copy of every configuration value as nested maps
+func (c *Config) AllSettings() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return copyMap(c.values)
+}
+
+// splitKey splits a dotted key into its lower-cased sections
+func splitKey(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(strings.ToLower(key), KeyDelimiter)
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles writes the files, by name, into a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml":      "cache:\n  driver: map\n  ttl: 1m\n  host: base\n  port: 1\n",
+		"config.prod.yaml": "cache:\n  driver: redis\n  host: prod\n",
+		"extra.json":       `{"cache": {"host": "extra"}}`,
+	})
+	t.Setenv("TEST_CACHE__PORT", "6379")
+
+	cfg := New(
+		Path(dir),
+		Profile("prod"),
+		EnvPrefix("TEST"),
+		Files("extra.json"),
+		Defaults(map[string]interface{}{"cache.ttl": "5m", "cache.prefix": "app_"}),
+	)
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "cache.prefix", want: "app_"},  // Defaults only
+		{key: "cache.ttl", want: "1m"},       // Base file over defaults
+		{key: "cache.driver", want: "redis"}, // Profile file over base file
+		{key: "cache.host", want: "extra"},   // Additional file over profile file
+		{key: "cache.port", want: "6379"},    // Environment over every file
+	}
+	for _, tt := range tests {
+		if got := cfg.GetString(tt.key); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+
+	if files := cfg.Files(); len(files) != 3 {
+		t.Errorf("files = %v, want the base, profile and extra files", files)
+	}
+}
+
+func TestProfileFromEnvironment(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml":         "name: base\n",
+		"config.staging.yaml": "name: staging\n",
+	})
+	t.Setenv("TEST_ENV", "staging")
+
+	cfg := New(Path(dir), EnvPrefix("TEST"))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Profile() != "staging" || cfg.GetString("name") != "staging" {
+		t.Fatalf("profile %q, name %q; want staging", cfg.Profile(), cfg.GetString("name"))
+	}
+	if cfg.Has("env") {
+		t.Fatal("TEST_ENV was loaded as a value")
+	}
+}
+
+func TestReadFileExpandsEnvReferences(t *testing.T) {
+	t.Setenv("DB_HOST", "db.internal")
+	t.Setenv("DB_USER", "app")
+
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "reference", yaml: "value: ${DB_HOST}", want: "db.internal"},
+		{name: "embedded references", yaml: `value: "${DB_USER}@${DB_HOST}:5432"`, want: "app@db.internal:5432"},
+		{name: "missing variable", yaml: "value: ${MISSING_VARIABLE}", want: ""},
+		{name: "bare dollar", yaml: "value: pa$word", want: "pa$word"},
+		{name: "double dollar", yaml: `value: "a$$b"`, want: "a$$b"},
+		{name: "unbraced variable", yaml: "value: $DB_HOST", want: "$DB_HOST"},
+		{name: "dollar at the end", yaml: "value: price$", want: "price$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{"config.yaml": tt.yaml})
+
+			values, err := readFile(filepath.Join(dir, "config.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if values["value"] != tt.want {
+				t.Fatalf("value = %q, want %q", values["value"], tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFileKeepsReferencesInKeysAndNonStrings(t *testing.T) {
+	t.Setenv("PORT", "8080")
+
+	dir := writeFiles(t, map[string]string{
+		"config.json": `{"server": {"port": 80, "hosts": ["${PORT}", "b"]}, "${PORT}": "key"}`,
+	})
+
+	values, err := readFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := values["server"].(map[string]interface{})
+	if server["port"] != float64(80) {
+		t.Errorf("port = %#v, want 80", server["port"])
+	}
+	if hosts := server["hosts"].([]interface{}); hosts[0] != "8080" {
+		t.Errorf("hosts[0] = %#v, want 8080", hosts[0])
+	}
+	if values["${port}"] != "key" {
+		t.Errorf("keys were expanded: %v", values)
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/HemendCo/go-core/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretScheme prefixes the configuration values that reference a secret, e.g. "secret://db/main/password"
+const SecretScheme = "secret://"
+
+// SensitiveKeys lists the words whose keys have their values redacted in dumps even if they are not
+// secret references. A key matches if it contains one of them, ignoring case, e.g. "db_password" or "apiToken".
+var SensitiveKeys = []string{"password", "passwd", "secret", "api_key", "apikey", "token", "master_key", "credential", "private_key"}
+
+// SecretResolver returns the secret stored at a path, the part of a reference after SecretScheme
+type SecretResolver interface {
+	Secret(path string) (string, error)
+}
+
+// UseSecrets resolves the secret references of the configuration with resolver,
+// now and on every later Load or Reload.
+func (c *Config) UseSecrets(resolver SecretResolver) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values, secretKeys, err := resolveSecrets(c.values, resolver)
+	if err != nil {
+		return err
+	}
+
+	c.resolver = resolver
+	c.values = values
+	c.secretKeys = secretKeys
+	return nil
+}
+
+// secretResolver returns the resolver set by UseSecrets
+func (c *Config) secretResolver() SecretResolver {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.resolver
+}
+
+// IsSecret reports whether the value at key was resolved from a secret reference
+func (c *Config) IsSecret(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.secretKeys[strings.ToLower(key)]
+}
+
+// Redacted returns a copy of every configuration value as nested maps, with the resolved
+// secrets and the values of SensitiveKeys replaced, safe to log or dump.
+func (c *Config) Redacted() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return redact(c.values, "", c.secretKeys).(map[string]interface{})
+}
+
+// String dumps the redacted configuration as YAML
+func (c *Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("<invalid configuration: %v>", err)
+	}
+	return string(data)
+}
+
+// resolveSecrets returns a copy of the values with the secret references resolved,
+// and the dotted keys of the resolved values
+func resolveSecrets(values map[string]interface{}, resolver SecretResolver) (map[string]interface{}, map[string]bool, error) {
+	secretKeys := make(map[string]bool)
+	if resolver == nil {
+		return values, secretKeys, nil
+	}
+
+	resolved, err := resolveValue(values, "", resolver, secretKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolved.(map[string]interface{}), secretKeys, nil
+}
+
+func resolveValue(value interface{}, path string, resolver SecretResolver, secretKeys map[string]bool) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			item, err := resolveValue(item, joinKey(path, key), resolver, secretKeys)
+			if err != nil {
+				return nil, err
+			}
+			res[key] = item
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			item, err := resolveValue(item, joinKey(path, strconv.Itoa(i)), resolver, secretKeys)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = item
+		}
+		return res, nil
+	case string:
+		ref, ok := strings.CutPrefix(v, SecretScheme)
+		if !ok {
+			return v, nil
+		}
+
+		secret, err := resolver.Secret(ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve secret of '%s': %w", path, err)
+		}
+
+		secretKeys[path] = true
+		return secret, nil
+	}
+	return value, nil
+}
+
+// redact returns a copy of the value with the secrets and the values of sensitive keys replaced
+func redact(value interface{}, path string, secretKeys map[string]bool) interface{} {
+	return redactValue(value, path, false, secretKeys)
+}
+
+// redactValue redacts the scalar values of secret paths and of sensitive keys, including the items of their lists
+func redactValue(value interface{}, path string, sensitive bool, secretKeys map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[key] = redactValue(item, joinKey(path, key), isSensitiveKey(key), secretKeys)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = redactValue(item, joinKey(path, strconv.Itoa(i)), sensitive, secretKeys)
+		}
+		return res
+	case nil:
+		return nil
+	}
+
+	if sensitive || secretKeys[path] {
+		return helpers.Redact(fmt.Sprint(value))
+	}
+	return value
+}
+
+// isSensitiveKey reports whether a key contains one of the SensitiveKeys, ignoring case
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range SensitiveKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinKey appends a section to a dotted key
+func joinKey(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + KeyDelimiter + key
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
+// mapResolver resolves the secrets of a map
+type mapResolver map[string]string
+
+func (r mapResolver) Secret(path string) (string, error) {
+	secret, ok := r[path]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := New(Path(t.TempDir()), EnvPrefix(""), Defaults(map[string]interface{}{
+		"database.connections.main.db_password": "pa$word",
+		"database.connections.main.host":        "localhost",
+		"database.connections.main.port":        5432,
+		"mail.smtp.smtp_password":               "mail-secret",
+		"mail.smtp.username":                    "noreply",
+		"cache.redis.password":                  12345,
+		"sms.hemend.apiToken":                   "abc",
+		"sms.hemend.api_keys":                   []interface{}{"k1", "k2"},
+		"sms.hemend.token_ttl":                  "",
+		"secrets.driver":                        "vault",
+		"secrets.vault.master_key":              "unseal-me",
+		"database.connections.main.dsn":         "secret://db/main/dsn",
+	}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.UseSecrets(mapResolver{"db/main/dsn": "postgres://app:pw@db/app"}); err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := cfg.Redacted()
+	get := func(key string) interface{} {
+		value, _ := lookup(redacted, splitKey(key))
+		return value
+	}
+
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{key: "database.connections.main.db_password", want: helpers.RedactedValue},
+		{key: "mail.smtp.smtp_password", want: helpers.RedactedValue},
+		{key: "cache.redis.password", want: helpers.RedactedValue}, // Not a string
+		{key: "sms.hemend.apitoken", want: helpers.RedactedValue},  // Keys are lower-cased
+		{key: "sms.hemend.api_keys", want: []interface{}{helpers.RedactedValue, helpers.RedactedValue}},
+		{key: "sms.hemend.token_ttl", want: ""}, // Empty values stay empty
+		{key: "secrets.vault.master_key", want: helpers.RedactedValue},
+		{key: "database.connections.main.dsn", want: helpers.RedactedValue}, // Resolved secret
+		{key: "database.connections.main.host", want: "localhost"},
+		{key: "database.connections.main.port", want: 5432},
+		{key: "mail.smtp.username", want: "noreply"},
+		{key: "secrets.driver", want: "vault"}, // Sections of sensitive names are not redacted as a whole
+	}
+	for _, tt := range tests {
+		got := get(tt.key)
+		if list, ok := tt.want.([]interface{}); ok {
+			items, _ := got.([]interface{})
+			if len(items) != len(list) || items[0] != list[0] || items[1] != list[1] {
+				t.Errorf("%s = %#v, want %#v", tt.key, got, tt.want)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %#v, want %#v", tt.key, got, tt.want)
+		}
+	}
+
+	dump := cfg.String()
+	for _, secret := range []string{"pa$word", "mail-secret", "12345", "postgres://", "unseal-me"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("the dump contains %q:\n%s", secret, dump)
+		}
+	}
+
+	if cfg.GetString("database.connections.main.db_password") != "pa$word" {
+		t.Error("redacting changed the configuration")
+	}
+}
+
+func TestUseSecretsReportsMissingSecrets(t *testing.T) {
+	cfg := New(Path(t.TempDir()), EnvPrefix(""), Defaults(map[string]interface{}{"db.password": "secret://missing"}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.UseSecrets(mapResolver{}); err == nil {
+		t.Fatal("UseSecrets succeeded with a missing secret")
+	}
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvNestingSeparator separates the sections of a key in environment variable names
+const EnvNestingSeparator = "__"
+
+// envReference matches a ${VAR} reference to an environment variable
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// readFile parses a YAML, JSON or TOML file. ${VAR} references in string values are
+// replaced by the environment variable, any other $ is kept as it is.
+func readFile(file string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file '%s': %w", file, err)
+	}
+
+	values := make(map[string]interface{})
+
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(file), ".")); ext {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(content, &values)
+	case "json":
+		err = json.Unmarshal(content, &values)
+	case "toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file format '%s'", ext)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file '%s': %w", file, err)
+	}
+
+	return expandEnv(normalize(values)).(map[string]interface{}), nil
+}
+
+// expandEnv replaces the ${VAR} references in the string values of the nested maps
+func expandEnv(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(v, func(reference string) string {
+			return os.Getenv(envReference.FindStringSubmatch(reference)[1])
+		})
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = expandEnv(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = expandEnv(item)
+		}
+	}
+	return value
+}
+
+// environment returns the values of the environment variables with the prefix, keyed by dotted keys
+func environment(prefix string) map[string]interface{} {
+	values := make(map[string]interface{})
+	if prefix == "" {
+		return values
+	}
+
+	prefix = strings.ToUpper(prefix) + "_"
+	for _, entry := range os.Environ() {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || name == prefix+"ENV" {
+			continue
+		}
+
+		key := strings.TrimPrefix(name, prefix)
+		key = strings.ReplaceAll(strings.ToLower(key), strings.ToLower(EnvNestingSeparator), KeyDelimiter)
+		values[key] = value
+	}
+
+	return values
+}
+
+// normalize converts nested maps to map[string]interface{} with lower-cased keys
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[strings.ToLower(key)] = normalize(item)
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[strings.ToLower(fmt.Sprint(key))] = normalize(item)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = normalize(item)
+		}
+		return res
+	case []map[string]interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = normalize(item)
+		}
+		return res
+	}
+	return value
+}
+
+// merge deeply merges src into dst, values of src taking precedence
+func merge(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			merge(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// setPath sets a value at the path, creating the intermediate sections
+func setPath(values map[string]interface{}, path []string, value interface{}) {
+	if len(path) == 0 {
+		if section, ok := value.(map[string]interface{}); ok {
+			merge(values, section)
+		}
+		return
+	}
+
+	for _, key := range path[:len(path)-1] {
+		section, ok := values[key].(map[string]interface{})
+		if !ok {
+			section = make(map[string]interface{})
+			values[key] = section
+		}
+		values = section
+	}
+
+	last := path[len(path)-1]
+	if section, ok := value.(map[string]interface{}); ok {
+		if existing, ok := values[last].(map[string]interface{}); ok {
+			merge(existing, section)
+			return
+		}
+	}
+	values[last] = value
+}
+
+// lookup returns the value at the path
+func lookup(values map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = values
+	for _, key := range path {
+		section, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = section[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// copyValue returns a deep copy of a value if it is a section
+func copyValue(value interface{}) interface{} {
+	if section, ok := value.(map[string]interface{}); ok {
+		return copyMap(section)
+	}
+	return value
+}
+
+// copyMap returns a deep copy of the nested maps
+func copyMap(values map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			res[key] = copyMap(v)
+		case []interface{}:
+			res[key] = append([]interface{}(nil), v...)
+		default:
+			res[key] = value
+		}
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ChangeEvent describes the change of a configuration section after a reload
+type ChangeEvent struct {
+	Key string      // The subscribed key, empty for the whole configuration
+	Old interface{} // The previous value, nil if it was not set
+	New interface{} // The current value, nil if it was removed
+}
+
+// subscription holds a change listener of a section
+type subscription struct {
+	id  int
+	key string
+	fn  func(event ChangeEvent)
+}
+
+// Subscribe registers fn to be called after a reload changes the value at key.
+// An empty key subscribes to any change. The returned function removes the subscription.
+func (c *Config) Subscribe(key string, fn func(event ChangeEvent)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastSubscription++
+	id := c.lastSubscription
+	c.subscriptions = append(c.subscriptions, subscription{id: id, key: strings.ToLower(key), fn: fn})
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, sub := range c.subscriptions {
+			if sub.id == id {
+				c.subscriptions = append(c.subscriptions[:i:i], c.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// OnChange registers fn to be called with the previous and current values of the section
+// at key bound into T after a reload changes it. Values that fail to bind are reported to
+// the log and skipped. The returned function removes the subscription.
+func OnChange[T any](c *Config, key string, fn func(old T, new T)) func() {
+	return c.Subscribe(key, func(event ChangeEvent) {
+		var oldValue, newValue T
+		if event.Old != nil {
+			if err := decode(key, event.Old, &oldValue); err != nil {
+				log.Printf("[Config] failed to bind previous value of '%s': %v", key, err)
+			}
+		}
+		if err := decode(key, event.New, &newValue); err != nil {
+			log.Printf("[Config] ignoring change of '%s': %v", key, err)
+			return
+		}
+		fn(oldValue, newValue)
+	})
+}
+
+// Reload reads the sources again and notifies the subscribers of the changed sections.
+// The previous values are kept if the sources cannot be read.
+func (c *Config) Reload() error {
+	values, files, secretKeys, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	previous := c.values
+	c.values = values
+	c.files = files
+	c.secretKeys = secretKeys
+	subscriptions := append([]subscription(nil), c.subscriptions...)
+	c.mu.Unlock()
+
+	notify(subscriptions, previous, values)
+	return nil
+}
+
+// notify calls the subscribers of the sections that differ between previous and current
+func notify(subscriptions []subscription, previous, current map[string]interface{}) {
+	for _, sub := range subscriptions {
+		oldValue, _ := lookup(previous, splitKey(sub.key))
+		newValue, _ := lookup(current, splitKey(sub.key))
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		sub.fn(ChangeEvent{Key: sub.key, Old: oldValue, New: newValue})
+	}
+}
+
+// Watch polls the configuration files and environment variables every interval and
+// reloads the configuration when they change, until ctx is done.
+// Reload failures are reported to the log and the previous values are kept.
+func (c *Config) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := c.fingerprint()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := c.fingerprint()
+		if current == last {
+			continue
+		}
+		last = current
+
+		if err := c.Reload(); err != nil {
+			log.Printf("[Config] failed to reload configuration: %v", err)
+		}
+	}
+}
+
+// fingerprint summarizes the state of the configuration sources
+func (c *Config) fingerprint() string {
+	var b strings.Builder
+
+	for _, file := range c.candidateFiles() {
+		info, err := os.Stat(file)
+		if err != nil {
+			fmt.Fprintf(&b, "%s:-;", file)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+	}
+
+	env := environment(c.opt.envPrefix)
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%v;", key, env[key])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the change events of a subscription
+type recorder struct {
+	mu     sync.Mutex
+	events []ChangeEvent
+}
+
+func (r *recorder) record(event ChangeEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.events)
+}
+
+func TestReloadKeepsOverridesAndNotifies(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": "cache:\n  driver: map\n  ttl: 1m\nmail:\n  driver: log\n",
+	})
+
+	cfg := New(Path(dir), EnvPrefix(""))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var cacheEvents, mailEvents, allEvents recorder
+	cfg.Subscribe("cache", cacheEvents.record)
+	cfg.Subscribe("mail", mailEvents.record)
+	cfg.Subscribe("", allEvents.record)
+
+	cfg.Set("cache.driver", "redis")
+	if cacheEvents.count() != 1 || mailEvents.count() != 0 || allEvents.count() != 1 {
+		t.Fatalf("after Set: %d cache, %d mail, %d global events; want 1, 0, 1", cacheEvents.count(), mailEvents.count(), allEvents.count())
+	}
+	if event := cacheEvents.events[0]; event.Old.(map[string]interface{})["driver"] != "map" || event.New.(map[string]interface{})["driver"] != "redis" {
+		t.Fatalf("Set event = %+v, want driver map -> redis", event)
+	}
+
+	// Setting the same value again changes nothing
+	cfg.Set("cache.driver", "redis")
+	if cacheEvents.count() != 1 {
+		t.Fatalf("unchanged Set notified the subscribers")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("cache:\n  driver: file\n  ttl: 5m\nmail:\n  driver: smtp\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "cache.driver", want: "redis"}, // Overridden by Set
+		{key: "cache.ttl", want: "5m"},       // Reloaded from the file
+		{key: "mail.driver", want: "smtp"},
+	}
+	for _, tt := range tests {
+		if got := cfg.GetString(tt.key); got != tt.want {
+			t.Errorf("%s = %q after reload, want %q", tt.key, got, tt.want)
+		}
+	}
+	if cacheEvents.count() != 2 || mailEvents.count() != 1 {
+		t.Fatalf("after reload: %d cache and %d mail events, want 2 and 1", cacheEvents.count(), mailEvents.count())
+	}
+}
+
+func TestOnChangeBindsSections(t *testing.T) {
+	cfg := New(Path(t.TempDir()), EnvPrefix(""), Defaults(map[string]interface{}{"server.port": 80}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	type server struct {
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+
+	var previous, current server
+	unsubscribe := OnChange(cfg, "server", func(old server, new server) {
+		previous, current = old, new
+	})
+
+	cfg.Set("server.timeout", "3s")
+	if previous.Port != 80 || current.Port != 80 || current.Timeout != 3*time.Second {
+		t.Fatalf("OnChange got %+v -> %+v", previous, current)
+	}
+
+	unsubscribe()
+	cfg.Set("server.port", 8080)
+	if current.Port != 80 {
+		t.Fatal("subscriber called after unsubscribing")
+	}
+}
+
+func TestWatchReloadsChangedFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.yaml": "name: first\n"})
+
+	cfg := New(Path(dir), EnvPrefix(""))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Set("region", "eu")
+
+	changed := make(chan ChangeEvent, 1)
+	cfg.Subscribe("name", func(event ChangeEvent) { changed <- event })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cfg.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // Let Watch take the initial fingerprint
+
+	// A different size changes the fingerprint even within the modification time resolution
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("name: second\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-changed:
+		if event.New != "second" {
+			t.Fatalf("new value = %v, want second", event.New)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the change was not detected")
+	}
+
+	if cfg.GetString("region") != "eu" {
+		t.Fatal("the override was lost by the watch reload")
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"github.com/HemendCo/go-core/database/db_config"
 	"github.com/HemendCo/go-core/database/db_interfaces"
@@ -13,6 +14,7 @@ import (
 type DBConnection struct {
 	connectionName string
 	driver         *db_interfaces.DatabaseDriver
+	ctx            context.Context
 }
 
 func CreateDBConnection(connectionName string, driver *db_interfaces.DatabaseDriver) db_interfaces.DatabaseConnection {
@@ -30,8 +32,13 @@ func (dc *DBConnection) DriverName() string {
 	return (*dc.driver).Name()
 }
 
+// DB returns the gorm session, bound to the parent context of the connection if any
 func (dc *DBConnection) DB() *gorm.DB {
-	return (*dc.driver).DB()
+	db := (*dc.driver).DB()
+	if dc.ctx != nil && db != nil {
+		return db.WithContext(dc.ctx)
+	}
+	return db
 }
 
 func (dc *DBConnection) Config() *db_config.DBConfig {
@@ -45,3 +52,23 @@ func (dc *DBConnection) SqlDB() (*sql.DB, error) {
 func (dc *DBConnection) MigrateDriver() (MigrateDB.Driver, error) {
 	return (*dc.driver).MigrateDriver()
 }
+
+// Close closes the underlying database connection pool
+func (dc *DBConnection) Close() error {
+	sqlDB, err := dc.SqlDB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
+
+// Health pings the database server
+func (dc *DBConnection) Health(ctx context.Context) error {
+	sqlDB, err := dc.SqlDB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
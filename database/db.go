@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/HemendCo/go-core/database/db_interfaces"
 	"log"
@@ -104,6 +106,28 @@ func (db *DB) MigrateDriver() (MigrateDB.Driver, error) {
 	return db.DefaultConnection().MigrateDriver()
 }
 
+// Close closes every connection of the DB
+func (db *DB) Close() error {
+	var errs []error
+	for name, conn := range db.connections {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close connection '%s': %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Health pings every connection of the DB
+func (db *DB) Health(ctx context.Context) error {
+	var errs []error
+	for name, conn := range db.connections {
+		if err := conn.Health(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("connection '%s': %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (db *DB) Migration(connectionNames ...string) error {
 	if len(connectionNames) == 0 {
 		connectionNamesList := make([]string, 0, len(db.connections))
@@ -1,12 +1,25 @@
 package db_config
 
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
 type DBConfig struct {
-	Driver              string `mapstructure:"driver"`
+	Driver              string `mapstructure:"driver" validate:"required"`
 	Host                string `mapstructure:"host"`
 	Port                string `mapstructure:"port"`
 	Username            string `mapstructure:"username"`
 	Password            string `mapstructure:"password"`
-	Database            string `mapstructure:"database"`
+	Database            string `mapstructure:"database" validate:"required"`
 	SchemaPath          string `mapstructure:"schema_path"`
-	IsDefaultConnection bool
+	IsDefaultConnection bool   `mapstructure:"is_default_connection"`
+}
+
+// String formats the configuration with the credentials redacted
+func (c DBConfig) String() string {
+	type plain DBConfig
+	c.Password = helpers.Redact(c.Password)
+	return fmt.Sprintf("%+v", plain(c))
 }
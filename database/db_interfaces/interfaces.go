@@ -1,6 +1,8 @@
 package db_interfaces
 
 import (
+	"context"
+
 	"github.com/HemendCo/go-core/database/db_config"
 
 	"database/sql"
@@ -27,6 +29,8 @@ type DatabaseConnection interface {
 	DB() *gorm.DB
 	SqlDB() (*sql.DB, error)
 	MigrateDriver() (database.Driver, error)
+	Close() error
+	Health(ctx context.Context) error
 }
 
 type DBConnector interface {
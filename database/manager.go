@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"github.com/HemendCo/go-core/database/db_config"
 	"github.com/HemendCo/go-core/database/db_drivers"
@@ -8,6 +9,7 @@ import (
 )
 
 type DatabaseManager struct {
+	ctx     context.Context
 	drivers map[string]db_interfaces.DatabaseDriver
 }
 
@@ -23,6 +25,13 @@ func NewDatabaseManager(drivers ...db_interfaces.DatabaseDriver) *DatabaseManage
 	return manager
 }
 
+// WithContext binds the queries of the connections created by the manager to ctx,
+// usually the root context of the application.
+func (dm *DatabaseManager) WithContext(ctx context.Context) *DatabaseManager {
+	dm.ctx = ctx
+	return dm
+}
+
 func (dm *DatabaseManager) RegisterDrivers(drivers ...db_interfaces.DatabaseDriver) {
 	for _, driver := range drivers {
 		dm.drivers[driver.Name()] = driver
@@ -41,5 +50,11 @@ func (dm *DatabaseManager) CreateDatabaseFactory(connectionName string, config d
 		return nil, err
 	}
 
-	return CreateDBConnection(connectionName, &driver), nil
+	conn := &DBConnection{
+		connectionName: connectionName,
+		driver:         &driver,
+		ctx:            dm.ctx,
+	}
+
+	return conn, nil
 }
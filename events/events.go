@@ -0,0 +1,297 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// NameSeparator separates the segments of an event name, e.g. "user.created"
+const NameSeparator = "."
+
+// ErrStopPropagation is returned by a synchronous listener to skip the remaining listeners.
+var ErrStopPropagation = errors.New("stop event propagation")
+
+// Event is a domain event identified by a dotted name, e.g. "user.created".
+// Events handed to queued listeners are encoded as JSON.
+type Event interface {
+	EventName() string
+}
+
+// Listener handles an event
+type Listener func(ctx context.Context, event Event) error
+
+type Option interface {
+}
+
+// mode selects how a listener is executed
+type mode int
+
+const (
+	syncMode mode = iota
+	asyncMode
+	queuedMode
+)
+
+// option holds the settings of a listener
+type option struct {
+	priority int
+	mode     mode
+	name     string
+}
+
+// Internal option representations.
+type (
+	priorityOption int
+	modeOption     mode
+	nameOption     string
+)
+
+// Priority returns an option to run the listener before the listeners of lower priority, 0 by default.
+func Priority(priority int) Option {
+	return priorityOption(priority)
+}
+
+// Async returns an option to run the listener in a goroutine; its errors are reported to the log.
+func Async() Option {
+	return modeOption(asyncMode)
+}
+
+// Queued returns an option to run the listener in the background through the worker of the
+// application. The processes running the worker must register the same listeners.
+func Queued() Option {
+	return modeOption(queuedMode)
+}
+
+// ListenerName returns an option to name the listener. Queued listeners are found by name
+// in the worker process; by default the name is derived from the pattern and the registration order.
+func ListenerName(name string) Option {
+	return nameOption(name)
+}
+
+func composeOptions(opts ...Option) option {
+	res := option{}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case priorityOption:
+			res.priority = int(opt)
+		case modeOption:
+			res.mode = mode(opt)
+		case nameOption:
+			res.name = string(opt)
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
+
+// subscription is a registered listener
+type subscription struct {
+	id       int
+	pattern  []string
+	listener Listener
+	opt      option
+}
+
+// Dispatcher delivers events to the listeners subscribed to their name.
+type Dispatcher struct {
+	app           *core.App
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	byName        map[string]*subscription // Listeners by name, to run queued listeners
+	counts        map[string]int           // Listeners by pattern, to name them
+	types         map[string]reflect.Type  // Event types by name, to decode queued events
+	lastID        int
+	wg            sync.WaitGroup // Running async listeners
+}
+
+// NewDispatcher creates a dispatcher whose queued listeners are enqueued on the worker of the app.
+func NewDispatcher(app *core.App) *Dispatcher {
+	return &Dispatcher{
+		app:    app,
+		byName: make(map[string]*subscription),
+		counts: make(map[string]int),
+		types:  make(map[string]reflect.Type),
+	}
+}
+
+// Listen subscribes the listener to the events whose name matches the pattern. In a pattern,
+// "*" matches one segment and "**" any number of segments: "user.*" matches "user.created",
+// "**" matches every event. The returned function removes the subscription.
+func (d *Dispatcher) Listen(pattern string, listener Listener, opts ...Option) func() {
+	opt := composeOptions(opts...)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if opt.name == "" {
+		d.counts[pattern]++
+		opt.name = fmt.Sprintf("%s#%d", pattern, d.counts[pattern])
+	}
+
+	d.lastID++
+	sub := &subscription{
+		id:       d.lastID,
+		pattern:  strings.Split(pattern, NameSeparator),
+		listener: listener,
+		opt:      opt,
+	}
+	d.subscriptions = append(d.subscriptions, sub)
+	d.byName[opt.name] = sub
+
+	// Higher priorities first, registration order otherwise
+	sort.SliceStable(d.subscriptions, func(i, j int) bool {
+		return d.subscriptions[i].opt.priority > d.subscriptions[j].opt.priority
+	})
+
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		for i, item := range d.subscriptions {
+			if item.id == sub.id {
+				d.subscriptions = append(d.subscriptions[:i:i], d.subscriptions[i+1:]...)
+				break
+			}
+		}
+		if d.byName[opt.name] == sub {
+			delete(d.byName, opt.name)
+		}
+	}
+}
+
+// Listen subscribes a listener to the events of type T, named by T.EventName.
+func Listen[T Event](d *Dispatcher, listener func(ctx context.Context, event T) error, opts ...Option) func() {
+	name := RegisterEvent[T](d)
+
+	return d.Listen(name, func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T for %s", event, name)
+		}
+		return listener(ctx, typed)
+	}, opts...)
+}
+
+// RegisterEvent registers the type T so that queued events of its name can be decoded,
+// and returns the name. Listen registers the type of its events.
+func RegisterEvent[T Event](d *Dispatcher) string {
+	eventType := reflect.TypeFor[T]()
+	name := newEvent(eventType).EventName()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.types[name] = eventType
+	return name
+}
+
+// Dispatch delivers the event to the matching listeners by priority. Synchronous listeners
+// run in order until one returns ErrStopPropagation; their errors are returned together.
+// Async listeners are started and queued listeners are enqueued as they are reached.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) error {
+	name := event.EventName()
+
+	var errs []error
+	for _, sub := range d.matching(name) {
+		switch sub.opt.mode {
+		case syncMode:
+			err := sub.listener(ctx, event)
+			if errors.Is(err, ErrStopPropagation) {
+				return errors.Join(errs...)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("listener %s of %s: %w", sub.opt.name, name, err))
+			}
+		case asyncMode:
+			d.wg.Add(1)
+			go func() {
+				defer d.wg.Done()
+
+				if err := sub.listener(context.WithoutCancel(ctx), event); err != nil && !errors.Is(err, ErrStopPropagation) {
+					log.Printf("[Events] listener %s of %s failed: %v", sub.opt.name, name, err)
+				}
+			}()
+		case queuedMode:
+			if err := d.enqueue(sub, event); err != nil {
+				errs = append(errs, fmt.Errorf("failed to queue listener %s of %s: %w", sub.opt.name, name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// HasListeners reports whether any listener is subscribed to the event name.
+func (d *Dispatcher) HasListeners(name string) bool {
+	return len(d.matching(name)) > 0
+}
+
+// Wait blocks until the running async listeners return.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Close waits for the running async listeners, so they finish before the application stops.
+func (d *Dispatcher) Close() error {
+	d.Wait()
+	return nil
+}
+
+// matching returns the listeners subscribed to the name, by priority
+func (d *Dispatcher) matching(name string) []*subscription {
+	segments := strings.Split(name, NameSeparator)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var res []*subscription
+	for _, sub := range d.subscriptions {
+		if match(sub.pattern, segments) {
+			res = append(res, sub)
+		}
+	}
+	return res
+}
+
+// match reports whether the segments of a name match the segments of a pattern
+func match(pattern []string, segments []string) bool {
+	for i, part := range pattern {
+		if part == "**" {
+			rest := pattern[i+1:]
+			for j := i; j <= len(segments); j++ {
+				if match(rest, segments[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if i >= len(segments) || (part != "*" && part != segments[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+// newEvent returns a zero event of the type, allocating pointer types
+func newEvent(eventType reflect.Type) Event {
+	if eventType.Kind() == reflect.Pointer {
+		return reflect.New(eventType.Elem()).Interface().(Event)
+	}
+	return reflect.New(eventType).Elem().Interface().(Event)
+}
+
+// worker resolves the worker of the application
+func (d *Dispatcher) worker() (worker_interfaces.WorkerDriver, error) {
+	return core.Resolve[worker_interfaces.WorkerDriver](d.app, core.WorkerKeyword)
+}
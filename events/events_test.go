@@ -0,0 +1,246 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// userCreated is the event of the tests
+type userCreated struct {
+	ID int `json:"id"`
+}
+
+func (e userCreated) EventName() string {
+	return "user.created"
+}
+
+// namedEvent is an event of any name
+type namedEvent string
+
+func (e namedEvent) EventName() string {
+	return string(e)
+}
+
+// queueWorker is a worker keeping the payloads of the enqueued jobs
+type queueWorker struct {
+	mu       sync.Mutex
+	payloads [][]byte
+}
+
+func (w *queueWorker) Name() string                                          { return "queue" }
+func (w *queueWorker) Init(app *core.App, config interface{}) error          { return nil }
+func (w *queueWorker) RegisterJobHandlers(handlers ...worker_interfaces.Job) {}
+func (w *queueWorker) JobHandlerExists(handler worker_interfaces.Job) bool   { return true }
+func (w *queueWorker) Close() error                                          { return nil }
+func (w *queueWorker) Run(handlers ...worker_interfaces.Job) error           { return nil }
+
+func (w *queueWorker) Enqueue(job worker_interfaces.Job, params interface{}) error {
+	task, err := job.NewTask(nil, params)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.payloads = append(w.payloads, payload)
+	return nil
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "user.created", name: "user.created", want: true},
+		{pattern: "user.created", name: "user.deleted", want: false},
+		{pattern: "user.*", name: "user.created", want: true},
+		{pattern: "user.*", name: "user", want: false},
+		{pattern: "user.*", name: "user.profile.updated", want: false},
+		{pattern: "*.created", name: "order.created", want: true},
+		{pattern: "user.**", name: "user.profile.updated", want: true},
+		{pattern: "user.**", name: "user", want: true},
+		{pattern: "**.updated", name: "user.profile.updated", want: true},
+		{pattern: "**.updated", name: "user.profile.created", want: false},
+		{pattern: "**", name: "anything.at.all", want: true},
+		{pattern: "user", name: "user.created", want: false},
+	}
+
+	for _, tt := range tests {
+		got := match(strings.Split(tt.pattern, NameSeparator), strings.Split(tt.name, NameSeparator))
+		if got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDispatchOrderAndErrors(t *testing.T) {
+	failure := errors.New("failed")
+
+	tests := []struct {
+		name      string
+		listeners map[string]error // Listener outcomes by name
+		wantCalls string
+		wantErr   bool
+	}{
+		{name: "priority order", wantCalls: "high,first,second,low"},
+		{name: "errors do not stop the listeners", listeners: map[string]error{"first": failure}, wantCalls: "high,first,second,low", wantErr: true},
+		{name: "stop propagation", listeners: map[string]error{"first": ErrStopPropagation}, wantCalls: "high,first"},
+		{name: "errors before stopping are returned", listeners: map[string]error{"high": failure, "second": ErrStopPropagation}, wantCalls: "high,first,second", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDispatcher(core.NewApp())
+
+			var calls []string
+			listener := func(name string) Listener {
+				return func(ctx context.Context, event Event) error {
+					calls = append(calls, name)
+					return tt.listeners[name]
+				}
+			}
+			d.Listen("user.created", listener("first"))
+			d.Listen("user.*", listener("second"))
+			d.Listen("user.**", listener("low"), Priority(-1))
+			d.Listen("**", listener("high"), Priority(10))
+			d.Listen("order.*", listener("other"))
+
+			err := d.Dispatch(context.Background(), userCreated{ID: 1})
+			if got := strings.Join(calls, ","); got != tt.wantCalls {
+				t.Errorf("calls = %s, want %s", got, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Dispatch() error = %v, want error %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrStopPropagation) {
+				t.Error("ErrStopPropagation was returned")
+			}
+		})
+	}
+}
+
+func TestTypedListenerAndUnsubscribe(t *testing.T) {
+	d := NewDispatcher(core.NewApp())
+
+	var ids []int
+	unsubscribe := Listen(d, func(ctx context.Context, event userCreated) error {
+		ids = append(ids, event.ID)
+		return nil
+	})
+
+	if !d.HasListeners("user.created") || d.HasListeners("user.deleted") {
+		t.Fatal("HasListeners does not match the typed listener")
+	}
+	if err := d.Dispatch(context.Background(), userCreated{ID: 7}); err != nil {
+		t.Fatal(err)
+	}
+	// An event of the same name but another type is reported
+	if err := d.Dispatch(context.Background(), namedEvent("user.created")); err == nil {
+		t.Fatal("the typed listener accepted an event of another type")
+	}
+
+	unsubscribe()
+	if err := d.Dispatch(context.Background(), userCreated{ID: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != 7 || d.HasListeners("user.created") {
+		t.Fatalf("ids = %v, want [7] and no listeners left", ids)
+	}
+}
+
+func TestAsyncListeners(t *testing.T) {
+	d := NewDispatcher(core.NewApp())
+
+	var mu sync.Mutex
+	count := 0
+	d.Listen("user.*", func(ctx context.Context, event Event) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		return errors.New("reported to the log")
+	}, Async())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 10; i++ {
+		if err := d.Dispatch(ctx, userCreated{ID: i}); err != nil {
+			t.Fatalf("the error of an async listener was returned: %v", err)
+		}
+	}
+	cancel() // Async listeners outlive the context of the dispatch
+
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Fatalf("%d async listeners ran, want 10", count)
+	}
+}
+
+func TestQueuedListeners(t *testing.T) {
+	app := core.NewApp()
+	worker := &queueWorker{}
+	if err := app.Use(core.WorkerKeyword, func() (interface{}, error) { return worker, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher(app)
+	var received []userCreated
+	Listen(d, func(ctx context.Context, event userCreated) error {
+		received = append(received, event)
+		return nil
+	}, Queued(), ListenerName("welcome"))
+
+	if err := d.Dispatch(context.Background(), userCreated{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 0 || len(worker.payloads) != 1 {
+		t.Fatalf("the listener ran %d times and %d jobs were queued, want 0 and 1", len(received), len(worker.payloads))
+	}
+
+	// The worker process runs the listener of the same name
+	if err := d.Job().Handler(app, worker.payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].ID != 3 {
+		t.Fatalf("received %v, want the event of ID 3", received)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "invalid payload", payload: `{`},
+		{name: "unknown listener", payload: `{"event":"user.created","listener":"missing","data":{}}`},
+		{name: "unregistered event", payload: `{"event":"order.created","listener":"welcome","data":{}}`},
+		{name: "invalid event data", payload: `{"event":"user.created","listener":"welcome","data":{"id":"x"}}`},
+	}
+	for _, tt := range tests {
+		if err := d.Job().Handler(app, []byte(tt.payload)); err == nil {
+			t.Errorf("%s: the job succeeded", tt.name)
+		}
+	}
+}
+
+func TestQueuedListenerWithoutWorker(t *testing.T) {
+	d := NewDispatcher(core.NewApp())
+	d.Listen("user.created", func(ctx context.Context, event Event) error { return nil }, Queued())
+
+	if err := d.Dispatch(context.Background(), userCreated{ID: 1}); err == nil {
+		t.Fatal("a queued listener was dispatched without a worker")
+	}
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/HemendCo/go-core"
+)
+
+// queuedEvent is the payload of the job running a queued listener
+type queuedEvent struct {
+	Event    string          `json:"event"`
+	Listener string          `json:"listener"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Job runs queued listeners in the worker. Register it with the worker that processes
+// the events, e.g. worker.Run(dispatcher.Job()).
+type Job struct {
+	dispatcher *Dispatcher
+}
+
+// Job returns the worker job of the dispatcher.
+func (d *Dispatcher) Job() *Job {
+	return &Job{dispatcher: d}
+}
+
+func (j *Job) TypeName() string {
+	return "events:listener"
+}
+
+func (j *Job) NewTask(app *core.App, params interface{}) (interface{}, error) {
+	task, ok := params.(queuedEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected params type %T for job %s", params, j.TypeName())
+	}
+	return task, nil
+}
+
+func (j *Job) Handler(app *core.App, payload []byte) error {
+	var task queuedEvent
+	if err := json.Unmarshal(payload, &task); err != nil {
+		return fmt.Errorf("invalid payload for job %s: %w", j.TypeName(), err)
+	}
+
+	return j.dispatcher.handle(app, task)
+}
+
+// enqueue hands the listener and the encoded event to the worker
+func (d *Dispatcher) enqueue(sub *subscription, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	worker, err := d.worker()
+	if err != nil {
+		return err
+	}
+
+	return worker.Enqueue(d.Job(), queuedEvent{
+		Event:    event.EventName(),
+		Listener: sub.opt.name,
+		Data:     data,
+	})
+}
+
+// handle decodes a queued event and runs its listener
+func (d *Dispatcher) handle(app *core.App, task queuedEvent) error {
+	d.mu.RLock()
+	sub, subscribed := d.byName[task.Listener]
+	eventType, registered := d.types[task.Event]
+	d.mu.RUnlock()
+
+	if !subscribed {
+		return fmt.Errorf("no listener %s registered for queued event %s", task.Listener, task.Event)
+	}
+	if !registered {
+		return fmt.Errorf("no event type registered for queued event %s, see RegisterEvent", task.Event)
+	}
+
+	target := reflect.New(eventType)
+	if err := json.Unmarshal(task.Data, target.Interface()); err != nil {
+		return fmt.Errorf("failed to decode queued event %s: %w", task.Event, err)
+	}
+
+	return sub.listener(app.GetContext(), target.Elem().Interface().(Event))
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Internal service option representations.
+type dependsOnOption []Keywords
+
+// DependsOn returns a service option that declares the services the service resolves.
+// Declared dependencies are checked by Validate and started before the service.
+func DependsOn(keys ...Keywords) ServiceOption {
+	return dependsOnOption(keys)
+}
+
+func (l Lifetime) String() string {
+	switch l {
+	case SingletonLifetime:
+		return "singleton"
+	case TransientLifetime:
+		return "transient"
+	case ScopedLifetime:
+		return "scoped"
+	}
+	return fmt.Sprintf("Lifetime(%d)", int(l))
+}
+
+// GraphNode is a service of the dependency graph
+type GraphNode struct {
+	Key        Keywords `json:"key"`
+	Lifetime   string   `json:"lifetime,omitempty"`
+	Registered bool     `json:"registered"`
+	Created    bool     `json:"created"`
+}
+
+// GraphEdge is a dependency of a service on another service
+type GraphEdge struct {
+	From Keywords `json:"from"`
+	To   Keywords `json:"to"`
+}
+
+// DependencyGraph describes the registered services and their declared dependencies
+type DependencyGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// JSON returns the graph encoded as indented JSON
+func (g DependencyGraph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT returns the graph in the Graphviz DOT format.
+// Missing dependencies are drawn dashed and created services filled.
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph services {\n")
+	for _, node := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", fmt.Sprintf("%s\n(%s)", node.Key, node.Lifetime))}
+		if !node.Registered {
+			attrs = []string{fmt.Sprintf("label=%q", fmt.Sprintf("%s\n(missing)", node.Key)), "style=dashed"}
+		} else if node.Created {
+			attrs = append(attrs, "style=filled")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", string(node.Key), strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", string(edge.From), string(edge.To))
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// DependencyGraph returns the registered services and their declared dependencies
+func (a *App) DependencyGraph() DependencyGraph {
+	a.lifecycle.mu.Lock()
+	order := append([]Keywords(nil), a.lifecycle.order...)
+	services := make(map[Keywords]*serviceDefinition, len(a.lifecycle.services))
+	for key, definition := range a.lifecycle.services {
+		services[key] = definition
+	}
+	a.lifecycle.mu.Unlock()
+
+	var graph DependencyGraph
+	seen := make(map[Keywords]bool)
+
+	for _, key := range order {
+		definition := services[key]
+		_, created := a.singleton.Instance(string(key))
+
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			Key:        key,
+			Lifetime:   definition.lifetime.String(),
+			Registered: true,
+			Created:    created,
+		})
+		seen[key] = true
+	}
+
+	for _, key := range order {
+		for _, dependency := range services[key].dependencies {
+			graph.Edges = append(graph.Edges, GraphEdge{From: key, To: dependency})
+
+			if !seen[dependency] {
+				graph.Nodes = append(graph.Nodes, GraphNode{Key: dependency})
+				seen[dependency] = true
+			}
+		}
+	}
+
+	return graph
+}
+
+// Validate checks the declared dependencies of the registered services.
+// It reports missing dependencies, dependency cycles and singleton services
+// depending on scoped services, which would outlive their scope.
+func (a *App) Validate() error {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+
+	var errs []error
+
+	for _, key := range a.lifecycle.order {
+		definition := a.lifecycle.services[key]
+		for _, dependency := range definition.dependencies {
+			target, ok := a.lifecycle.services[dependency]
+			if !ok {
+				errs = append(errs, fmt.Errorf("[App] service %s depends on missing service %s", key, dependency))
+				continue
+			}
+
+			if definition.lifetime == SingletonLifetime && target.lifetime == ScopedLifetime {
+				errs = append(errs, fmt.Errorf("[App] singleton service %s depends on scoped service %s", key, dependency))
+			}
+		}
+	}
+
+	for _, cycle := range a.lifecycle.cycles() {
+		path := make([]string, len(cycle))
+		for i, key := range cycle {
+			path[i] = string(key)
+		}
+		errs = append(errs, fmt.Errorf("[App] dependency cycle detected: %s", strings.Join(path, " -> ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+// cycles returns the dependency cycles between registered services, the lock must be held
+func (l *lifecycle) cycles() [][]Keywords {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var cycles [][]Keywords
+	state := make(map[Keywords]int)
+	var stack []Keywords
+
+	var visit func(key Keywords)
+	visit = func(key Keywords) {
+		state[key] = visiting
+		stack = append(stack, key)
+
+		if definition, ok := l.services[key]; ok {
+			for _, dependency := range definition.dependencies {
+				switch state[dependency] {
+				case unvisited:
+					visit(dependency)
+				case visiting:
+					// The dependency is on the current path, so the path from it back to itself is a cycle
+					for i := len(stack) - 1; i >= 0; i-- {
+						if stack[i] == dependency {
+							cycle := append(append([]Keywords(nil), stack[i:]...), dependency)
+							cycles = append(cycles, cycle)
+							break
+						}
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[key] = visited
+	}
+
+	for _, key := range l.order {
+		if state[key] == unvisited {
+			visit(key)
+		}
+	}
+
+	return cycles
+}
+
+// startOrder returns the singleton services ordered so that declared dependencies
+// come before their dependents, keeping the registration order otherwise. The lock must be held.
+func (l *lifecycle) startOrder() []Keywords {
+	var order []Keywords
+	visited := make(map[Keywords]bool)
+
+	var visit func(key Keywords)
+	visit = func(key Keywords) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+
+		definition, ok := l.services[key]
+		if !ok {
+			return
+		}
+
+		for _, dependency := range definition.dependencies {
+			visit(dependency)
+		}
+
+		if definition.lifetime == SingletonLifetime {
+			order = append(order, key)
+		}
+	}
+
+	for _, key := range l.order {
+		visit(key)
+	}
+
+	return order
+}
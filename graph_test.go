@@ -0,0 +1,239 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// registration is a service registered by the graph tests
+type registration struct {
+	key          Keywords
+	lifetime     Lifetime
+	dependencies []Keywords
+}
+
+// newGraphApp registers the services with their declared dependencies
+func newGraphApp(t *testing.T, services []registration) *App {
+	t.Helper()
+
+	app := NewApp()
+	for _, service := range services {
+		var err error
+		opts := []ServiceOption{DependsOn(service.dependencies...)}
+		newInstance := func() (interface{}, error) { return new(int), nil }
+		switch service.lifetime {
+		case SingletonLifetime:
+			err = app.Use(service.key, newInstance, opts...)
+		case TransientLifetime:
+			err = app.UseTransient(service.key, newInstance, opts...)
+		case ScopedLifetime:
+			err = app.UseScoped(service.key, func(scope *Scope) (interface{}, error) { return newInstance() }, opts...)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return app
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []registration
+		wantErrs []string // Every error reported, none if empty
+	}{
+		{
+			name: "dependency chain",
+			services: []registration{
+				{key: "http", dependencies: []Keywords{"cache", "database"}},
+				{key: "cache", dependencies: []Keywords{"database"}},
+				{key: "database"},
+			},
+		},
+		{
+			name:     "missing dependency",
+			services: []registration{{key: "http", dependencies: []Keywords{"cache"}}},
+			wantErrs: []string{"service http depends on missing service cache"},
+		},
+		{
+			name:     "self dependency",
+			services: []registration{{key: "cache", dependencies: []Keywords{"cache"}}},
+			wantErrs: []string{"dependency cycle detected: cache -> cache"},
+		},
+		{
+			name: "direct cycle",
+			services: []registration{
+				{key: "a", dependencies: []Keywords{"b"}},
+				{key: "b", dependencies: []Keywords{"a"}},
+			},
+			wantErrs: []string{"dependency cycle detected: a -> b -> a"},
+		},
+		{
+			name: "indirect cycle",
+			services: []registration{
+				{key: "entry", dependencies: []Keywords{"a"}},
+				{key: "a", dependencies: []Keywords{"b"}},
+				{key: "b", dependencies: []Keywords{"c"}},
+				{key: "c", dependencies: []Keywords{"a"}},
+			},
+			wantErrs: []string{"dependency cycle detected: a -> b -> c -> a"},
+		},
+		{
+			name: "cycle and missing dependency",
+			services: []registration{
+				{key: "a", dependencies: []Keywords{"b", "missing"}},
+				{key: "b", dependencies: []Keywords{"a"}},
+			},
+			wantErrs: []string{"service a depends on missing service missing", "dependency cycle detected: a -> b -> a"},
+		},
+		{
+			name: "singleton depending on a scoped service",
+			services: []registration{
+				{key: "repository", dependencies: []Keywords{"transaction"}},
+				{key: "transaction", lifetime: ScopedLifetime},
+			},
+			wantErrs: []string{"singleton service repository depends on scoped service transaction"},
+		},
+		{
+			name: "scoped and transient services depending on scoped services",
+			services: []registration{
+				{key: "handler", lifetime: TransientLifetime, dependencies: []Keywords{"transaction"}},
+				{key: "repository", lifetime: ScopedLifetime, dependencies: []Keywords{"transaction"}},
+				{key: "transaction", lifetime: ScopedLifetime},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newGraphApp(t, tt.services)
+
+			err := app.Validate()
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() succeeded, want %q", tt.wantErrs)
+			}
+			if got := len(strings.Split(err.Error(), "\n")); got != len(tt.wantErrs) {
+				t.Errorf("%d errors reported, want %d: %v", got, len(tt.wantErrs), err)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want %q", err, want)
+				}
+			}
+
+			// An invalid application does not start
+			if err := app.Start(context.Background()); err == nil {
+				t.Fatal("Start succeeded with invalid dependencies")
+			}
+		})
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	app := newGraphApp(t, []registration{
+		{key: "http", dependencies: []Keywords{"cache", "auth"}},
+		{key: "cache", dependencies: []Keywords{"database"}},
+		{key: "database"},
+		{key: "request", lifetime: ScopedLifetime, dependencies: []Keywords{"database", "logger"}},
+	})
+	if _, err := app.Get("database"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantDOT := `digraph services {
+  "http" [label="http\n(singleton)"];
+  "cache" [label="cache\n(singleton)"];
+  "database" [label="database\n(singleton)", style=filled];
+  "request" [label="request\n(scoped)"];
+  "auth" [label="auth\n(missing)", style=dashed];
+  "logger" [label="logger\n(missing)", style=dashed];
+  "http" -> "cache";
+  "http" -> "auth";
+  "cache" -> "database";
+  "request" -> "database";
+  "request" -> "logger";
+}
+`
+	wantJSON := `{
+  "nodes": [
+    {
+      "key": "http",
+      "lifetime": "singleton",
+      "registered": true,
+      "created": false
+    },
+    {
+      "key": "cache",
+      "lifetime": "singleton",
+      "registered": true,
+      "created": false
+    },
+    {
+      "key": "database",
+      "lifetime": "singleton",
+      "registered": true,
+      "created": true
+    },
+    {
+      "key": "request",
+      "lifetime": "scoped",
+      "registered": true,
+      "created": false
+    },
+    {
+      "key": "auth",
+      "registered": false,
+      "created": false
+    },
+    {
+      "key": "logger",
+      "registered": false,
+      "created": false
+    }
+  ],
+  "edges": [
+    {
+      "from": "http",
+      "to": "cache"
+    },
+    {
+      "from": "http",
+      "to": "auth"
+    },
+    {
+      "from": "cache",
+      "to": "database"
+    },
+    {
+      "from": "request",
+      "to": "database"
+    },
+    {
+      "from": "request",
+      "to": "logger"
+    }
+  ]
+}`
+
+	// The dumps follow the registration order, so they are the same on every call
+	for i := 0; i < 10; i++ {
+		graph := app.DependencyGraph()
+		if got := graph.DOT(); got != wantDOT {
+			t.Fatalf("DOT() =\n%s\nwant\n%s", got, wantDOT)
+		}
+		got, err := graph.JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != wantJSON {
+			t.Fatalf("JSON() =\n%s\nwant\n%s", got, wantJSON)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"github.com/HemendCo/go-core/health"
+)
+
+// Health returns the health registry of the application. Created singleton services
+// implementing health.Checker are checked for readiness under their key; register
+// additional checks with Register. The registry is ready once the application is started.
+func (a *App) Health() *health.Registry {
+	return a.health
+}
+
+// serviceCheckers returns the created singleton services implementing health.Checker
+func (a *App) serviceCheckers() map[string]health.Checker {
+	a.lifecycle.mu.Lock()
+	created := append([]Keywords(nil), a.lifecycle.created...)
+	a.lifecycle.mu.Unlock()
+
+	checkers := make(map[string]health.Checker, len(created))
+	for _, key := range created {
+		instance, ok := a.singleton.Instance(string(key))
+		if !ok {
+			continue
+		}
+		if checker, ok := instance.(health.Checker); ok {
+			checkers[string(key)] = checker
+		}
+	}
+	return checkers
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Handler serves the liveness report on /healthz and the readiness report on /readyz.
+// Mount it on a mux, e.g. mux.Handle("/healthz", handler) and mux.Handle("/readyz", handler).
+func Handler(registry *Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", LivenessHandler(registry))
+	mux.Handle("/readyz", ReadinessHandler(registry))
+	return mux
+}
+
+// LivenessHandler serves the liveness report, with status 503 if it is down.
+func LivenessHandler(registry *Registry) http.Handler {
+	return reportHandler(registry.Liveness)
+}
+
+// ReadinessHandler serves the readiness report, with status 503 if it is down.
+func ReadinessHandler(registry *Registry) http.Handler {
+	return reportHandler(registry.Readiness)
+}
+
+func reportHandler(report func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := report(r.Context())
+
+		status := http.StatusOK
+		if result.Status != StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+
+		if r.Method != http.MethodHead {
+			json.NewEncoder(w).Encode(result)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds a check that has no timeout of its own
+const DefaultTimeout = 5 * time.Second
+
+// Checker is implemented by the services and drivers that can report their health,
+// e.g. by pinging their server.
+type Checker interface {
+	Health(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to a Checker
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Health(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Status is the state of a check or of a report
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type Option interface {
+}
+
+// option holds the settings of a check
+type option struct {
+	timeout  time.Duration
+	liveness bool
+}
+
+// Internal option representations.
+type (
+	timeoutOption  time.Duration
+	livenessOption bool
+)
+
+// Timeout returns an option to specify how long a check may run, DefaultTimeout by default.
+func Timeout(timeout time.Duration) Option {
+	return timeoutOption(timeout)
+}
+
+// Liveness returns an option to include the check in the liveness report. A failing liveness
+// check means the process should be restarted, so only checks of the process itself belong there.
+// Every check is included in the readiness report.
+func Liveness() Option {
+	return livenessOption(true)
+}
+
+func composeOptions(defaultTimeout time.Duration, opts ...Option) option {
+	res := option{
+		timeout: defaultTimeout,
+	}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case timeoutOption:
+			res.timeout = time.Duration(opt)
+		case livenessOption:
+			res.liveness = bool(opt)
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Status   Status        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report aggregates the results of the checks; it is up when every check is up
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Err returns the failures of the report, or nil if it is up
+func (r Report) Err() error {
+	names := make([]string, 0, len(r.Checks))
+	for name := range r.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if result := r.Checks[name]; result.Status != StatusUp {
+			errs = append(errs, fmt.Errorf("%s: %s", name, result.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// check is a registered checker with its settings
+type check struct {
+	checker Checker
+	opt     option
+}
+
+// Registry holds the health checks of an application
+type Registry struct {
+	mu       sync.RWMutex
+	timeout  time.Duration
+	checks   map[string]check
+	discover []func() map[string]Checker
+	ready    atomic.Bool
+}
+
+// NewRegistry creates a Registry whose checks time out after DefaultTimeout unless they set their own Timeout.
+func NewRegistry() *Registry {
+	return &Registry{
+		timeout: DefaultTimeout,
+		checks:  make(map[string]check),
+	}
+}
+
+// SetTimeout changes the timeout of the checks registered without a Timeout option
+func (r *Registry) SetTimeout(timeout time.Duration) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timeout = timeout
+	return r
+}
+
+// Register adds a check under the name, replacing any check of the same name.
+func (r *Registry) Register(name string, checker Checker, opts ...Option) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks[name] = check{checker: checker, opt: composeOptions(0, opts...)}
+}
+
+// Unregister removes the check of the name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.checks, name)
+}
+
+// Discover adds a source of readiness checks evaluated on every report, e.g. the services
+// created by an application. Registered checks take precedence over discovered checks of the same name.
+func (r *Registry) Discover(fn func() map[string]Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.discover = append(r.discover, fn)
+}
+
+// SetReady marks whether the application is ready to receive traffic, e.g. once it is started
+func (r *Registry) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Ready reports whether the application is marked ready
+func (r *Registry) Ready() bool {
+	return r.ready.Load()
+}
+
+// Liveness runs the checks registered with the Liveness option.
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, r.collect(true))
+}
+
+// Readiness runs every check; the report is down while the application is not marked ready.
+func (r *Registry) Readiness(ctx context.Context) Report {
+	report := r.run(ctx, r.collect(false))
+	if !r.Ready() {
+		report.Status = StatusDown
+		report.Checks["app"] = Result{Status: StatusDown, Error: "application is not ready"}
+	}
+	return report
+}
+
+// collect returns the checks to run
+func (r *Registry) collect(liveness bool) map[string]check {
+	r.mu.RLock()
+	defaultTimeout := r.timeout
+	checks := make(map[string]check, len(r.checks))
+	for name, c := range r.checks {
+		if liveness && !c.opt.liveness {
+			continue
+		}
+		checks[name] = c
+	}
+	discover := append([]func() map[string]Checker(nil), r.discover...)
+	r.mu.RUnlock()
+
+	for name, c := range checks {
+		if c.opt.timeout == 0 {
+			c.opt.timeout = defaultTimeout
+			checks[name] = c
+		}
+	}
+
+	if liveness {
+		return checks
+	}
+
+	for _, fn := range discover {
+		for name, checker := range fn() {
+			if _, exists := checks[name]; !exists {
+				checks[name] = check{checker: checker, opt: option{timeout: defaultTimeout}}
+			}
+		}
+	}
+
+	return checks
+}
+
+// run executes the checks concurrently, each bounded by its timeout
+func (r *Registry) run(ctx context.Context, checks map[string]check) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := runCheck(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runCheck executes a check and stops waiting for it once its timeout passes
+func runCheck(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.opt.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- c.checker.Health(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	result := Result{Status: StatusUp, Duration: time.Since(start)}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// up and down are checkers with a fixed outcome
+var (
+	up   = CheckerFunc(func(ctx context.Context) error { return nil })
+	down = CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+)
+
+// blocking is a checker that waits until its context is done
+var blocking = CheckerFunc(func(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond) // Report late, the check must not wait for it
+	return ctx.Err()
+})
+
+func TestReports(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(r *Registry)
+		ready         bool
+		wantLiveness  Status
+		wantReadiness Status
+		wantChecks    map[string]Status // The checks of the readiness report
+	}{
+		{
+			name:          "no checks",
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusUp,
+			wantChecks:    map[string]Status{},
+		},
+		{
+			name:          "not ready",
+			setup:         func(r *Registry) { r.Register("db", up) },
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDown,
+			wantChecks:    map[string]Status{"db": StatusUp, "app": StatusDown},
+		},
+		{
+			name: "failing readiness check",
+			setup: func(r *Registry) {
+				r.Register("process", up, Liveness())
+				r.Register("db", down)
+			},
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDown,
+			wantChecks:    map[string]Status{"process": StatusUp, "db": StatusDown},
+		},
+		{
+			name:          "failing liveness check",
+			setup:         func(r *Registry) { r.Register("process", down, Liveness()) },
+			ready:         true,
+			wantLiveness:  StatusDown,
+			wantReadiness: StatusDown,
+			wantChecks:    map[string]Status{"process": StatusDown},
+		},
+		{
+			name:          "check timeout",
+			setup:         func(r *Registry) { r.Register("slow", blocking, Timeout(10*time.Millisecond)) },
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDown,
+			wantChecks:    map[string]Status{"slow": StatusDown},
+		},
+		{
+			name: "registry timeout",
+			setup: func(r *Registry) {
+				r.SetTimeout(10 * time.Millisecond)
+				r.Register("slow", blocking)
+			},
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDown,
+			wantChecks:    map[string]Status{"slow": StatusDown},
+		},
+		{
+			name: "panicking check",
+			setup: func(r *Registry) {
+				r.Register("broken", CheckerFunc(func(ctx context.Context) error { panic("boom") }))
+			},
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusDown,
+			wantChecks:    map[string]Status{"broken": StatusDown},
+		},
+		{
+			name: "discovered checks",
+			setup: func(r *Registry) {
+				r.Register("cache", up)
+				r.Discover(func() map[string]Checker {
+					return map[string]Checker{"cache": down, "queue": up}
+				})
+			},
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusUp, // The registered cache check takes precedence
+			wantChecks:    map[string]Status{"cache": StatusUp, "queue": StatusUp},
+		},
+		{
+			name: "unregistered check",
+			setup: func(r *Registry) {
+				r.Register("db", down, Liveness())
+				r.Unregister("db")
+			},
+			ready:         true,
+			wantLiveness:  StatusUp,
+			wantReadiness: StatusUp,
+			wantChecks:    map[string]Status{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+			r.SetReady(tt.ready)
+
+			ctx := context.Background()
+			if got := r.Liveness(ctx).Status; got != tt.wantLiveness {
+				t.Errorf("liveness = %s, want %s", got, tt.wantLiveness)
+			}
+
+			report := r.Readiness(ctx)
+			if report.Status != tt.wantReadiness {
+				t.Errorf("readiness = %s, want %s", report.Status, tt.wantReadiness)
+			}
+			if len(report.Checks) != len(tt.wantChecks) {
+				t.Errorf("checks = %v, want %v", report.Checks, tt.wantChecks)
+			}
+			for name, want := range tt.wantChecks {
+				if got := report.Checks[name].Status; got != want {
+					t.Errorf("check %s = %s, want %s", name, got, want)
+				}
+			}
+			if (report.Err() == nil) != (report.Status == StatusUp) {
+				t.Errorf("Err() = %v for a report that is %s", report.Err(), report.Status)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Register("process", up, Liveness())
+	r.Register("db", down)
+	handler := Handler(r)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		ready  bool
+		want   int
+	}{
+		{name: "liveness", method: http.MethodGet, path: "/healthz", want: http.StatusOK},
+		{name: "readiness down", method: http.MethodGet, path: "/readyz", ready: true, want: http.StatusServiceUnavailable},
+		{name: "head", method: http.MethodHead, path: "/readyz", want: http.StatusServiceUnavailable},
+		{name: "unknown path", method: http.MethodGet, path: "/status", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.SetReady(tt.ready)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.method == http.MethodHead && rec.Body.Len() != 0 {
+				t.Fatalf("HEAD returned a body: %s", rec.Body)
+			}
+		})
+	}
+}
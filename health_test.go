@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/HemendCo/go-core/health"
+)
+
+// checkedService is a service reporting a fixed health
+type checkedService struct {
+	err error
+}
+
+func (s *checkedService) Health(ctx context.Context) error {
+	return s.err
+}
+
+func TestHealthChecksCreatedServices(t *testing.T) {
+	app := NewApp()
+	ctx := context.Background()
+
+	services := map[string]*checkedService{
+		"db":    {},
+		"cache": {err: errors.New("connection refused")},
+	}
+	for key, service := range services {
+		if err := app.Use(Keywords(key), func() (interface{}, error) { return service, nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.Use("plain", func() (interface{}, error) { return "not a checker", nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if report := app.Health().Readiness(ctx); report.Status != health.StatusDown || len(report.Checks) != 1 {
+		t.Fatalf("readiness before Start = %+v, want only the app check down", report)
+	}
+
+	if err := app.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	report := app.Health().Readiness(ctx)
+	tests := []struct {
+		check string
+		want  health.Status
+	}{
+		{check: "db", want: health.StatusUp},
+		{check: "cache", want: health.StatusDown},
+	}
+	for _, tt := range tests {
+		if got := report.Checks[tt.check].Status; got != tt.want {
+			t.Errorf("check %s = %q, want %q", tt.check, got, tt.want)
+		}
+	}
+	if _, ok := report.Checks["plain"]; ok {
+		t.Error("a service that is not a health.Checker was checked")
+	}
+
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if app.Health().Ready() {
+		t.Error("the application is still ready after Shutdown")
+	}
+}
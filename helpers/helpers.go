@@ -28,3 +28,14 @@ func StringInArray(arr []string, str string) bool {
 	}
 	return false
 }
+
+// RedactedValue replaces sensitive values in logs and dumps
+const RedactedValue = "******"
+
+// Redact hides a sensitive value, keeping empty values empty so missing credentials stay visible
+func Redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return RedactedValue
+}
@@ -33,3 +33,22 @@ func JoinWithProjectPath(relativePath ...string) string {
 	// Construct the full path by joining the project directory and the relative path
 	return fullPath
 }
+
+// CheckWritableDir reports an error if the directory is missing or files cannot be created in it
+func CheckWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	file, err := os.CreateTemp(dir, ".health-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	return os.Remove(file.Name())
+}
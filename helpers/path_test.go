@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckWritableDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{name: "writable directory", dir: dir},
+		{name: "missing directory", dir: filepath.Join(dir, "missing"), wantErr: true},
+		{name: "file", dir: file, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckWritableDir(tt.dir); (err != nil) != tt.wantErr {
+				t.Fatalf("CheckWritableDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The probe neither creates the missing directory nor leaves files behind
+	if _, err := os.Stat(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Fatalf("the missing directory was created: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("%d entries in the directory, want 1", len(entries))
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/HemendCo/go-core"
+)
+
+// Context gives a handler access to the request, the response and the services of the application.
+// It implements core.Resolver, so core.Resolve[T](c, key) resolves from the request scope.
+type Context struct {
+	Writer  http.ResponseWriter
+	Request *http.Request
+	scope   *core.Scope
+}
+
+// Context returns the context of the request, cancelled when the request ends or the application shuts down.
+func (c *Context) Context() context.Context {
+	return c.Request.Context()
+}
+
+// Scope returns the scope of the request.
+func (c *Context) Scope() *core.Scope {
+	return c.scope
+}
+
+// App returns the application.
+func (c *Context) App() *core.App {
+	return c.scope.App()
+}
+
+// Get resolves a service from the scope of the request.
+func (c *Context) Get(key core.Keywords) (interface{}, error) {
+	return c.scope.Get(key)
+}
+
+// Param returns the value of a path wildcard, e.g. "id" in "/users/{id}".
+func (c *Context) Param(name string) string {
+	return c.Request.PathValue(name)
+}
+
+// Query returns the first value of a query parameter.
+func (c *Context) Query(name string) string {
+	return c.Request.URL.Query().Get(name)
+}
+
+// Bind decodes the JSON body of the request into target; decoding errors are 400 errors.
+func (c *Context) Bind(target interface{}) error {
+	if err := json.NewDecoder(c.Request.Body).Decode(target); err != nil {
+		return NewError(http.StatusBadRequest, "invalid request body").Wrap(err)
+	}
+	return nil
+}
+
+// JSON writes value as a JSON response.
+func (c *Context) JSON(status int, value interface{}) error {
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(status)
+	return json.NewEncoder(c.Writer).Encode(value)
+}
+
+// String writes a plain text response.
+func (c *Context) String(status int, text string) error {
+	c.Writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	c.Writer.WriteHeader(status)
+	_, err := c.Writer.Write([]byte(text))
+	return err
+}
+
+// NoContent writes a response without body.
+func (c *Context) NoContent(status int) error {
+	c.Writer.WriteHeader(status)
+	return nil
+}
+
+// Error is an error with the HTTP status and message written to the client.
+type Error struct {
+	Code    int
+	Message string
+	Err     error // The cause, not written to the client
+}
+
+// NewError creates an error answered with the status code and message.
+func NewError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap sets the cause of the error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DefaultErrorHandler writes {"error": message} with the status of an *Error,
+// or logs the error and answers 500 for any other error.
+func DefaultErrorHandler(c *Context, err error) {
+	var httpErr *Error
+	if !errors.As(err, &httpErr) {
+		log.Printf("[HTTP] %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		httpErr = NewError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	c.JSON(httpErr.Code, map[string]string{"error": httpErr.Message})
+}
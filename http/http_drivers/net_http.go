@@ -0,0 +1,122 @@
+package http_drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/http/http_models"
+)
+
+// NetHTTPDriver serves requests with the net/http server of the standard library.
+type NetHTTPDriver struct {
+	app      *core.App
+	cfg      http_models.NetHTTPConfig
+	mu       sync.Mutex
+	server   *http.Server
+	listener net.Listener
+}
+
+func (n *NetHTTPDriver) Name() string {
+	return "nethttp"
+}
+
+func (n *NetHTTPDriver) Init(app *core.App, config interface{}) error {
+	cfg, ok := config.(http_models.NetHTTPConfig)
+	if !ok {
+		return errors.New("invalid net/http configuration: expected a http_models.NetHTTPConfig type")
+	}
+
+	n.app = app
+	n.cfg = cfg
+
+	return nil
+}
+
+// Start listens on the configured address and serves the handler in the background.
+// Requests are bound to the root context of the application.
+func (n *NetHTTPDriver) Start(handler http.Handler) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.server != nil {
+		return errors.New("http server already started")
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(n.cfg.Host, n.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", net.JoinHostPort(n.cfg.Host, n.cfg.Port), err)
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadTimeout:       n.cfg.ReadTimeout,
+		ReadHeaderTimeout: n.cfg.ReadHeaderTimeout,
+		WriteTimeout:      n.cfg.WriteTimeout,
+		IdleTimeout:       n.cfg.IdleTimeout,
+		MaxHeaderBytes:    n.cfg.MaxHeaderBytes,
+		BaseContext: func(net.Listener) context.Context {
+			return n.app.GetContext()
+		},
+	}
+
+	n.server = server
+	n.listener = listener
+
+	go func() {
+		var err error
+		if n.cfg.CertFile != "" && n.cfg.KeyFile != "" {
+			err = server.ServeTLS(listener, n.cfg.CertFile, n.cfg.KeyFile)
+		} else {
+			err = server.Serve(listener)
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[HTTP] server on %s stopped: %v", listener.Addr(), err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops accepting connections and waits for the active requests to finish.
+func (n *NetHTTPDriver) Shutdown(ctx context.Context) error {
+	n.mu.Lock()
+	server := n.server
+	n.server = nil
+	n.listener = nil
+	n.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	return server.Shutdown(ctx)
+}
+
+// Addr returns the address the server listens on, or the configured address if it is not started.
+func (n *NetHTTPDriver) Addr() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.listener != nil {
+		return n.listener.Addr().String()
+	}
+	return net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+}
+
+// Health reports whether the server is listening.
+func (n *NetHTTPDriver) Health(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.server == nil {
+		return errors.New("http server is not started")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package http_models
+
+import "time"
+
+type NetHTTPConfig struct {
+	Host              string        `mapstructure:"host"`
+	Port              string        `mapstructure:"port" validate:"required"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+	CertFile          string        `mapstructure:"cert_file"` // Serves HTTPS when set with KeyFile
+	KeyFile           string        `mapstructure:"key_file"`
+}
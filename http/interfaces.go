@@ -0,0 +1,19 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/HemendCo/go-core"
+)
+
+type HTTPDriver interface {
+	Name() string
+	Init(app *core.App, config interface{}) error
+	// Start listens and serves the handler in the background; listen errors are returned.
+	Start(handler http.Handler) error
+	// Shutdown stops accepting connections and waits for the active requests to finish.
+	Shutdown(ctx context.Context) error
+	// Addr returns the address the driver listens on.
+	Addr() string
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/http/http_drivers"
+)
+
+type HTTPManager struct {
+	app     *core.App
+	drivers map[string]HTTPDriver
+}
+
+func NewHTTPManager(app *core.App, drivers ...HTTPDriver) *HTTPManager {
+	manager := &HTTPManager{
+		app:     app,
+		drivers: make(map[string]HTTPDriver),
+	}
+
+	// register default driver
+	drivers = append(drivers, &http_drivers.NetHTTPDriver{})
+	manager.RegisterDrivers(drivers...)
+
+	return manager
+}
+
+func (hm *HTTPManager) RegisterDrivers(drivers ...HTTPDriver) {
+	for _, driver := range drivers {
+		hm.drivers[driver.Name()] = driver
+	}
+}
+
+func (hm *HTTPManager) CreateHTTPFactory(driverName string, config interface{}) (HTTPDriver, error) {
+	driver, exists := hm.drivers[driverName]
+	if !exists {
+		return nil, fmt.Errorf("unsupported http driver %s", driverName)
+	}
+
+	if err := driver.Init(hm.app, config); err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
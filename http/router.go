@@ -0,0 +1,165 @@
+package http
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/HemendCo/go-core"
+)
+
+// HandlerFunc handles a request; a returned error is written by the error handler of the router.
+type HandlerFunc func(c *Context) error
+
+// Middleware wraps a handler, e.g. to authenticate or log requests.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// ErrorHandler writes the response of an error returned by a handler.
+type ErrorHandler func(c *Context, err error)
+
+// routes holds the state shared by a router and its groups
+type routes struct {
+	app          *core.App
+	mux          *http.ServeMux
+	errorHandler ErrorHandler
+}
+
+// Router registers routes on a net/http ServeMux, with the method and wildcard patterns
+// of the standard library, e.g. router.GET("/users/{id}", handler).
+// Every request is served within its own core.Scope, closed when the request ends.
+type Router struct {
+	routes     *routes
+	prefix     string
+	middleware []Middleware
+}
+
+// NewRouter creates a router whose handlers resolve services from the app.
+func NewRouter(app *core.App) *Router {
+	return &Router{
+		routes: &routes{
+			app:          app,
+			mux:          http.NewServeMux(),
+			errorHandler: DefaultErrorHandler,
+		},
+	}
+}
+
+// Use appends middleware to the router; it applies to the routes registered afterwards.
+func (r *Router) Use(middleware ...Middleware) *Router {
+	r.middleware = append(r.middleware, middleware...)
+	return r
+}
+
+// Group returns a router whose routes are prefixed with prefix and wrapped with the
+// middleware of this router followed by the given middleware.
+func (r *Router) Group(prefix string, middleware ...Middleware) *Router {
+	return &Router{
+		routes:     r.routes,
+		prefix:     r.path(prefix),
+		middleware: append(append([]Middleware(nil), r.middleware...), middleware...),
+	}
+}
+
+// SetErrorHandler replaces DefaultErrorHandler for the router and all its groups.
+func (r *Router) SetErrorHandler(handler ErrorHandler) *Router {
+	r.routes.errorHandler = handler
+	return r
+}
+
+// Handle registers the handler for the method and path; an empty method matches every method.
+func (r *Router) Handle(method string, path string, handler HandlerFunc) {
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+
+	routes := r.routes
+	routes.mux.HandleFunc(r.pattern(method, path), func(w http.ResponseWriter, req *http.Request) {
+		c := &Context{Writer: w, Request: req, scope: ScopeFromRequest(req)}
+		if err := handler(c); err != nil {
+			routes.errorHandler(c, err)
+		}
+	})
+}
+
+// Mount registers a net/http handler for the method and path, e.g. health.Handler at "/healthz".
+// The middleware of the router does not apply to mounted handlers.
+func (r *Router) Mount(method string, path string, handler http.Handler) {
+	r.routes.mux.Handle(r.pattern(method, path), handler)
+}
+
+func (r *Router) GET(path string, handler HandlerFunc) {
+	r.Handle(http.MethodGet, path, handler)
+}
+
+func (r *Router) POST(path string, handler HandlerFunc) {
+	r.Handle(http.MethodPost, path, handler)
+}
+
+func (r *Router) PUT(path string, handler HandlerFunc) {
+	r.Handle(http.MethodPut, path, handler)
+}
+
+func (r *Router) PATCH(path string, handler HandlerFunc) {
+	r.Handle(http.MethodPatch, path, handler)
+}
+
+func (r *Router) DELETE(path string, handler HandlerFunc) {
+	r.Handle(http.MethodDelete, path, handler)
+}
+
+// ServeHTTP serves the request within a new scope of the application.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	scope := r.routes.app.NewScope(req.Context())
+	defer scope.Close()
+
+	req = req.WithContext(context.WithValue(scope.Context(), scopeContextKey{}, scope))
+	r.routes.mux.ServeHTTP(w, req)
+}
+
+// path joins the prefix of the router and a path
+func (r *Router) path(path string) string {
+	if path == "" {
+		return r.prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.TrimSuffix(r.prefix, "/") + path
+}
+
+// pattern returns the ServeMux pattern of a route
+func (r *Router) pattern(method string, path string) string {
+	path = r.path(path)
+	if path == "" {
+		path = "/"
+	}
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
+
+// scopeContextKey stores the scope of a request in its context
+type scopeContextKey struct{}
+
+// ScopeFromRequest returns the scope of a request served by a Router, or nil.
+func ScopeFromRequest(req *http.Request) *core.Scope {
+	scope, _ := req.Context().Value(scopeContextKey{}).(*core.Scope)
+	return scope
+}
+
+// Recover returns a middleware that turns a panic of the handler into a 500 error.
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					log.Printf("[HTTP] panic serving %s %s: %v", c.Request.Method, c.Request.URL.Path, p)
+					err = NewError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				}
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/http/http_drivers"
+	"github.com/HemendCo/go-core/http/http_models"
+)
+
+// trace returns a middleware appending its name to the X-Trace header of the response
+func trace(name string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			c.Writer.Header().Add("X-Trace", name)
+			return next(c)
+		}
+	}
+}
+
+// newTestRouter creates a router with the routes exercised by TestRouter
+func newTestRouter() *Router {
+	router := NewRouter(core.NewApp())
+	router.Use(trace("root"))
+
+	router.GET("/users/{id}", func(c *Context) error {
+		return c.String(http.StatusOK, "user "+c.Param("id")+" "+c.Query("fields"))
+	})
+	router.POST("/users", func(c *Context) error {
+		var user struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind(&user); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, map[string]string{"name": user.Name})
+	})
+	router.DELETE("/users/{id}", func(c *Context) error {
+		return NewError(http.StatusForbidden, "not allowed")
+	})
+	router.GET("/failure", func(c *Context) error {
+		return errors.New("database password leaked in the message")
+	})
+
+	api := router.Group("/api", trace("api")).Use(Recover())
+	api.GET("/ping", func(c *Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	api.GET("/panic", func(c *Context) error {
+		panic("boom")
+	})
+
+	router.Mount("", "/healthz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	return router
+}
+
+func TestRouter(t *testing.T) {
+	router := newTestRouter()
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      string
+		wantCode  int
+		wantBody  string // A substring of the response body
+		wantTrace string
+	}{
+		{name: "params and query", method: http.MethodGet, path: "/users/42?fields=name", wantCode: http.StatusOK, wantBody: "user 42 name", wantTrace: "root"},
+		{name: "json body", method: http.MethodPost, path: "/users", body: `{"name":"ada"}`, wantCode: http.StatusCreated, wantBody: `{"name":"ada"}`, wantTrace: "root"},
+		{name: "invalid body", method: http.MethodPost, path: "/users", body: `{`, wantCode: http.StatusBadRequest, wantBody: "invalid request body", wantTrace: "root"},
+		{name: "http error", method: http.MethodDelete, path: "/users/1", wantCode: http.StatusForbidden, wantBody: `{"error":"not allowed"}`, wantTrace: "root"},
+		{name: "internal error is not exposed", method: http.MethodGet, path: "/failure", wantCode: http.StatusInternalServerError, wantBody: "Internal Server Error", wantTrace: "root"},
+		{name: "method not allowed", method: http.MethodPut, path: "/users/1", wantCode: http.StatusMethodNotAllowed},
+		{name: "not found", method: http.MethodGet, path: "/missing", wantCode: http.StatusNotFound},
+		{name: "group", method: http.MethodGet, path: "/api/ping", wantCode: http.StatusNoContent, wantTrace: "root,api"},
+		{name: "recovered panic", method: http.MethodGet, path: "/api/panic", wantCode: http.StatusInternalServerError, wantTrace: "root,api"},
+		{name: "mounted handler without middleware", method: http.MethodHead, path: "/healthz", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body, tt.wantBody)
+			}
+			if strings.Contains(rec.Body.String(), "password") {
+				t.Errorf("body exposes the internal error: %q", rec.Body)
+			}
+			if trace := strings.Join(rec.Header().Values("X-Trace"), ","); trace != tt.wantTrace {
+				t.Errorf("middleware = %q, want %q", trace, tt.wantTrace)
+			}
+		})
+	}
+}
+
+func TestRouterServesRequestsInScopes(t *testing.T) {
+	app := core.NewApp()
+
+	var created, stopped atomic.Int32
+	if err := app.UseScoped("request_id", func(scope *core.Scope) (interface{}, error) {
+		return created.Add(1), nil
+	}, core.OnStop(func(ctx context.Context, instance interface{}) error {
+		stopped.Add(1)
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter(app)
+	router.GET("/", func(c *Context) error {
+		first, err := c.Get("request_id")
+		if err != nil {
+			return err
+		}
+		second, _ := core.Resolve[int32](c, "request_id")
+		if first != second || ScopeFromRequest(c.Request) != c.Scope() {
+			return errors.New("the request does not have a single scope")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	for i := 1; i <= 2; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d (%s)", i, rec.Code, rec.Body)
+		}
+		if created.Load() != int32(i) || stopped.Load() != int32(i) {
+			t.Fatalf("after request %d: %d created, %d stopped; want %d each", i, created.Load(), stopped.Load(), i)
+		}
+	}
+}
+
+func TestServerLifecycle(t *testing.T) {
+	app := core.NewApp()
+	driver, err := NewHTTPManager(app).CreateHTTPFactory("nethttp", http_models.NetHTTPConfig{Host: "127.0.0.1", Port: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(app, driver)
+	server.Router().GET("/hello", func(c *Context) error {
+		return c.String(http.StatusOK, "hello")
+	})
+
+	ctx := context.Background()
+	if err := server.Health(ctx); err == nil {
+		t.Fatal("the server is healthy before it is started")
+	}
+	if err := server.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Start(ctx); err == nil {
+		t.Fatal("the server started twice")
+	}
+	if err := server.Health(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Get("http://" + server.Addr() + "/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != "hello" {
+		t.Fatalf("body = %q, want hello", body)
+	}
+
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := http.Get("http://" + server.Addr() + "/hello"); err == nil {
+		t.Fatal("the server still serves after Shutdown")
+	}
+
+	if _, err := NewHTTPManager(app).CreateHTTPFactory("fasthttp", nil); err == nil {
+		t.Fatal("an unsupported driver was created")
+	}
+	if err := (&http_drivers.NetHTTPDriver{}).Init(app, "not a config"); err == nil {
+		t.Fatal("Init accepted an invalid configuration")
+	}
+}
+
+func TestServerStartWithCancelledContext(t *testing.T) {
+	app := core.NewApp()
+	driver, err := NewHTTPManager(app).CreateHTTPFactory("nethttp", http_models.NetHTTPConfig{Host: "127.0.0.1", Port: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(app, driver)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := server.Start(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Start() error = %v, want %v", err, context.Canceled)
+	}
+	if err := server.Health(context.Background()); err == nil {
+		t.Fatal("the server listens after a cancelled Start")
+	}
+
+	// The server can still be started with a live context
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+
+	"github.com/HemendCo/go-core"
+)
+
+// Server is the HTTP service of an application: a driver serving a router.
+// Register the routes before the application starts; Start and Shutdown are
+// run by the application lifecycle when the server is registered by the bootstrapper.
+type Server struct {
+	driver HTTPDriver
+	router *Router
+}
+
+// NewServer creates a server whose router resolves services from the app.
+func NewServer(app *core.App, driver HTTPDriver) *Server {
+	return &Server{
+		driver: driver,
+		router: NewRouter(app),
+	}
+}
+
+// Router returns the router of the server.
+func (s *Server) Router() *Router {
+	return s.router
+}
+
+// Driver returns the driver of the server.
+func (s *Server) Driver() HTTPDriver {
+	return s.driver
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.driver.Addr()
+}
+
+// Start listens and serves the router in the background. It does not bind the port
+// when ctx is already cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.driver.Start(s.router)
+}
+
+// Shutdown stops accepting connections and waits for the active requests to finish.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.driver.Shutdown(ctx)
+}
+
+// Health reports the health of the driver, if it can report it.
+func (s *Server) Health(ctx context.Context) error {
+	if checker, ok := s.driver.(interface{ Health(context.Context) error }); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
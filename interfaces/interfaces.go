@@ -2,13 +2,16 @@ package interfaces
 
 import (
 	"context"
+	"time"
+
 	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/health"
 )
 
 // AppInterface defines the methods for the App structure.
 type AppInterface interface {
 	// GetContext returns the application context.
-	GetContext() *context.Context
+	GetContext() context.Context
 
 	// Config returns the configuration of the application.
 	Config() interface{}
@@ -16,6 +19,9 @@ type AppInterface interface {
 	// SetConfig sets the configuration of the application.
 	SetConfig(config interface{}) *core.App
 
+	// WatchConfig reloads the loaded configuration when its sources change.
+	WatchConfig(interval time.Duration) error
+
 	// RootPath returns the root path of the application.
 	RootPath() string
 
@@ -26,11 +32,50 @@ type AppInterface interface {
 	RegisterPlugin(path string, config interface{}) error
 
 	// Use registers a key with a create function for dependency management.
-	Use(key string, createFunc func() (interface{}, error)) error
+	Use(key core.Keywords, createFunc func() (interface{}, error), opts ...core.ServiceOption) error
+
+	// UseTransient registers a key whose create function runs on every resolution.
+	UseTransient(key core.Keywords, createFunc func() (interface{}, error), opts ...core.ServiceOption) error
+
+	// UseScoped registers a key whose create function runs once per scope.
+	UseScoped(key core.Keywords, createFunc func(scope *core.Scope) (interface{}, error), opts ...core.ServiceOption) error
+
+	// NewScope creates a child container bound to the context.
+	NewScope(ctx context.Context) *core.Scope
 
 	// Get retrieves the value associated with the key.
-	Get(key string) (interface{}, error)
+	Get(key core.Keywords) (interface{}, error)
+
+	// Instance returns the created instance of a key without creating it.
+	Instance(key core.Keywords) (interface{}, bool)
 
 	// Exists checks if a key is registered.
-	Exists(key string) bool
+	Exists(key core.Keywords) bool
+
+	// Reset drops the created instance of a key so it is created again.
+	Reset(ctx context.Context, key core.Keywords) error
+
+	// Replace hot-swaps the create function of a key.
+	Replace(ctx context.Context, key core.Keywords, createFunc func() (interface{}, error)) error
+
+	// Health returns the health registry of the application.
+	Health() *health.Registry
+
+	// Validate checks the declared dependencies of the registered keys.
+	Validate() error
+
+	// DependencyGraph returns the registered keys and their declared dependencies.
+	DependencyGraph() core.DependencyGraph
+
+	// Start creates the registered services and runs their start hooks.
+	Start(ctx context.Context) error
+
+	// Shutdown stops the created services in reverse creation order.
+	Shutdown(ctx context.Context) error
+
+	// Run starts the application and shuts it down on SIGINT/SIGTERM.
+	Run(ctx context.Context) error
 }
+
+// Ensure *core.App implements AppInterface.
+var _ AppInterface = (*core.App)(nil)
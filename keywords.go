@@ -0,0 +1,79 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Keywords is the key a service is registered under. Besides the built-in keywords,
+// any valid name such as "mailer" or a namespaced name such as "payments:stripe" can be used.
+type Keywords string
+
+const (
+	CacheKeyword         Keywords = "cache"
+	LoggerKeyword        Keywords = "logger"
+	CLIKeyword           Keywords = "cli"
+	DatabaseKeyword      Keywords = "database"
+	WorkerKeyword        Keywords = "worker"
+	SMSKeyword           Keywords = "sms"
+	SecretsKeyword       Keywords = "secrets"
+	HTTPKeyword          Keywords = "http"
+	EventsKeyword        Keywords = "events"
+	MailKeyword          Keywords = "mail"
+	NotificationsKeyword Keywords = "notifications"
+	LocksKeyword         Keywords = "locks"
+	SchedulerKeyword     Keywords = "scheduler"
+	PluginManagerKeyword Keywords = "pluginManager"
+)
+
+// NamespaceSeparator separates the namespace from the name of a service key.
+const NamespaceSeparator = ":"
+
+var keywordPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+(:[A-Za-z0-9_.\-]+)*$`)
+
+// IsValid reports whether the key can be used to register a service.
+func (k Keywords) IsValid() bool {
+	return keywordPattern.MatchString(string(k))
+}
+
+// IsBuiltin reports whether the key is one of the keywords reserved by the framework.
+func (k Keywords) IsBuiltin() bool {
+	switch k {
+	case CacheKeyword, LoggerKeyword, CLIKeyword, DatabaseKeyword, WorkerKeyword, SMSKeyword, SecretsKeyword, HTTPKeyword, EventsKeyword, MailKeyword, NotificationsKeyword, LocksKeyword, SchedulerKeyword, PluginManagerKeyword:
+		return true
+	}
+	return false
+}
+
+// Namespace returns the namespace of the key, or an empty string if it has none.
+func (k Keywords) Namespace() string {
+	if i := strings.LastIndex(string(k), NamespaceSeparator); i >= 0 {
+		return string(k[:i])
+	}
+	return ""
+}
+
+// Name returns the key without its namespace.
+func (k Keywords) Name() string {
+	if i := strings.LastIndex(string(k), NamespaceSeparator); i >= 0 {
+		return string(k[i+len(NamespaceSeparator):])
+	}
+	return string(k)
+}
+
+func (k Keywords) String() string {
+	return string(k)
+}
+
+// Namespace groups the keys of related services, e.g. Namespace("payments").Key("stripe").
+type Namespace string
+
+// Key returns the key of the named service within the namespace.
+func (n Namespace) Key(name string) Keywords {
+	return Keywords(string(n) + NamespaceSeparator + name)
+}
+
+// Namespace returns a nested namespace.
+func (n Namespace) Namespace(name string) Namespace {
+	return Namespace(string(n) + NamespaceSeparator + name)
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		key           Keywords
+		wantValid     bool
+		wantBuiltin   bool
+		wantNamespace string
+		wantName      string
+	}{
+		{key: CacheKeyword, wantValid: true, wantBuiltin: true, wantName: "cache"},
+		{key: PluginManagerKeyword, wantValid: true, wantBuiltin: true, wantName: "pluginManager"},
+		{key: "mailer", wantValid: true, wantName: "mailer"},
+		{key: "payments:stripe", wantValid: true, wantNamespace: "payments", wantName: "stripe"},
+		{key: "tenant-1:payments:stripe.v2", wantValid: true, wantNamespace: "tenant-1:payments", wantName: "stripe.v2"},
+		{key: "snake_case", wantValid: true, wantName: "snake_case"},
+		// A built-in name within a namespace is a custom key
+		{key: "payments:cache", wantValid: true, wantNamespace: "payments", wantName: "cache"},
+		{key: "", wantValid: false},
+		{key: "with space", wantValid: false},
+		{key: ":stripe", wantValid: false},
+		{key: "payments:", wantValid: false},
+		{key: "payments::stripe", wantValid: false},
+		{key: "payments/stripe", wantValid: false},
+		{key: "émail", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			if got := tt.key.IsValid(); got != tt.wantValid {
+				t.Fatalf("IsValid() = %v, want %v", got, tt.wantValid)
+			}
+			if !tt.wantValid {
+				return
+			}
+			if got := tt.key.IsBuiltin(); got != tt.wantBuiltin {
+				t.Errorf("IsBuiltin() = %v, want %v", got, tt.wantBuiltin)
+			}
+			if got := tt.key.Namespace(); got != tt.wantNamespace {
+				t.Errorf("Namespace() = %q, want %q", got, tt.wantNamespace)
+			}
+			if got := tt.key.Name(); got != tt.wantName {
+				t.Errorf("Name() = %q, want %q", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	payments := Namespace("payments")
+
+	tests := []struct {
+		key  Keywords
+		want Keywords
+	}{
+		{key: payments.Key("stripe"), want: "payments:stripe"},
+		{key: payments.Namespace("eu").Key("stripe"), want: "payments:eu:stripe"},
+	}
+	for _, tt := range tests {
+		if tt.key != tt.want {
+			t.Errorf("key %q, want %q", tt.key, tt.want)
+		}
+	}
+}
+
+func TestUseCustomKeys(t *testing.T) {
+	app := NewApp()
+	payments := Namespace("payments")
+
+	// The same name in two namespaces registers two services
+	for _, key := range []Keywords{"mailer", payments.Key("stripe"), Namespace("billing").Key("stripe")} {
+		if err := app.Use(key, func() (interface{}, error) { return string(key), nil }); err != nil {
+			t.Fatalf("Use(%q): %v", key, err)
+		}
+	}
+	if got := MustResolve[string](app, payments.Key("stripe")); got != "payments:stripe" {
+		t.Fatalf("resolved %q, want payments:stripe", got)
+	}
+
+	tests := []struct {
+		name string
+		use  func() error
+	}{
+		{name: "Use with an invalid key", use: func() error { return app.Use("with space", func() (interface{}, error) { return nil, nil }) }},
+		{name: "UseTransient with an invalid key", use: func() error { return app.UseTransient(":stripe", func() (interface{}, error) { return nil, nil }) }},
+		{name: "UseScoped with an invalid key", use: func() error {
+			return app.UseScoped("payments:", func(scope *Scope) (interface{}, error) { return nil, nil })
+		}},
+		{name: "duplicate key", use: func() error { return app.Use("mailer", func() (interface{}, error) { return nil, nil }) }},
+	}
+	for _, tt := range tests {
+		if err := tt.use(); err == nil {
+			t.Errorf("%s did not fail", tt.name)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Hook is executed against a service instance while the application starts or stops.
+type Hook func(ctx context.Context, instance interface{}) error
+
+type ServiceOption interface {
+}
+
+// Internal service option representations.
+type (
+	onStartOption Hook
+	onStopOption  Hook
+)
+
+// OnStart returns a service option that runs the hook when the application starts.
+func OnStart(hook Hook) ServiceOption {
+	return onStartOption(hook)
+}
+
+// OnStop returns a service option that runs the hook when the application shuts down.
+// Services without a stop hook are stopped through their Shutdown(ctx) or Close() method, if any.
+func OnStop(hook Hook) ServiceOption {
+	return onStopOption(hook)
+}
+
+// serviceDefinition holds the lifetime and lifecycle hooks registered for a service
+type serviceDefinition struct {
+	lifetime     Lifetime
+	onStart      []Hook
+	onStop       []Hook
+	dependencies []Keywords
+}
+
+// lifecycle tracks registered and created services of an App
+type lifecycle struct {
+	mu       sync.Mutex
+	order    []Keywords // Services in registration order
+	created  []Keywords // Services in creation order, dependencies first
+	services map[Keywords]*serviceDefinition
+	started  bool
+}
+
+func composeServiceOptions(lifetime Lifetime, opts ...ServiceOption) *serviceDefinition {
+	res := &serviceDefinition{lifetime: lifetime}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case onStartOption:
+			res.onStart = append(res.onStart, Hook(opt))
+		case onStopOption:
+			res.onStop = append(res.onStop, Hook(opt))
+		case dependsOnOption:
+			res.dependencies = append(res.dependencies, opt...)
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
+
+// register records a service and its definition
+func (l *lifecycle) register(key Keywords, definition *serviceDefinition) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.services == nil {
+		l.services = make(map[Keywords]*serviceDefinition)
+	}
+	l.order = append(l.order, key)
+	l.services[key] = definition
+}
+
+// definition returns the definition registered for a service
+func (l *lifecycle) definition(key Keywords) *serviceDefinition {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.services[key]
+}
+
+// track wraps a creator function so that successful creations are recorded in order
+func (l *lifecycle) track(key Keywords, createFunc func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		instance, err := createFunc()
+		if err == nil && instance != nil {
+			l.mu.Lock()
+			l.created = append(l.created, key)
+			l.mu.Unlock()
+		}
+		return instance, err
+	}
+}
+
+// forget removes a service from the created services
+func (l *lifecycle) forget(key Keywords) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, created := range l.created {
+		if created == key {
+			l.created = append(l.created[:i:i], l.created[i+1:]...)
+			return
+		}
+	}
+}
+
+// isStarted reports whether the application has been started
+func (l *lifecycle) isStarted() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.started
+}
+
+// Start validates the registered services and the required configuration sections, creates every singleton service with its declared
+// dependencies first and runs their start hooks in creation order.
+// If a service fails to be created or started, Start is rolled back, see rollback,
+// and the application can be started again.
+func (a *App) Start(ctx context.Context) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	if cfg := a.Configuration(); cfg != nil {
+		if err := cfg.ValidateRequired(); err != nil {
+			return fmt.Errorf("[App] invalid configuration: %w", err)
+		}
+	}
+
+	a.lifecycle.mu.Lock()
+	if a.lifecycle.started {
+		a.lifecycle.mu.Unlock()
+		return errors.New("[App] application already started")
+	}
+	a.lifecycle.started = true
+	services := a.lifecycle.startOrder()
+	existing := make(map[Keywords]bool, len(a.lifecycle.created))
+	for _, key := range a.lifecycle.created {
+		existing[key] = true
+	}
+	a.lifecycle.mu.Unlock()
+
+	// Creating a service also creates the services it resolves during construction
+	for _, key := range services {
+		if _, err := a.Get(key); err != nil {
+			return errors.Join(err, a.rollback(ctx, existing, nil))
+		}
+	}
+
+	a.lifecycle.mu.Lock()
+	created := append([]Keywords(nil), a.lifecycle.created...)
+	a.lifecycle.mu.Unlock()
+
+	for i, key := range created {
+		if err := a.runHooks(ctx, key, false); err != nil {
+			return errors.Join(fmt.Errorf("[App] failed to start %s: %w", key, err), a.rollback(ctx, existing, created[:i]))
+		}
+	}
+
+	a.health.SetReady(true)
+	return nil
+}
+
+// rollback undoes a failed Start. The services whose start hooks ran are stopped in reverse
+// creation order and dropped; the other services created during Start are dropped without
+// being stopped. The next Start creates them again. Unlike Shutdown, the root context is
+// not cancelled.
+func (a *App) rollback(ctx context.Context, existing map[Keywords]bool, started []Keywords) error {
+	wasStarted := make(map[Keywords]bool, len(started))
+	for _, key := range started {
+		wasStarted[key] = true
+	}
+
+	a.lifecycle.mu.Lock()
+	created := append([]Keywords(nil), a.lifecycle.created...)
+	a.lifecycle.started = false
+	a.lifecycle.mu.Unlock()
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		key := created[i]
+		switch {
+		case wasStarted[key]:
+			if err := a.Reset(ctx, key); err != nil {
+				errs = append(errs, fmt.Errorf("[App] failed to roll back %s: %w", key, err))
+			}
+		case !existing[key]:
+			a.lifecycle.forget(key)
+			if err := a.singleton.Reset(string(key)); err != nil {
+				errs = append(errs, fmt.Errorf("[App] failed to roll back %s: %w", key, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shutdown stops every created service in reverse creation order, so that a service
+// is stopped before the services it depends on. The context bounds the whole shutdown.
+// The root context of the application is cancelled once the services are stopped,
+// or as soon as the shutdown deadline is exceeded.
+func (a *App) Shutdown(ctx context.Context) error {
+	// Stop receiving traffic before the services stop
+	a.health.SetReady(false)
+
+	a.lifecycle.mu.Lock()
+	created := a.lifecycle.created
+	a.lifecycle.created = nil
+	a.lifecycle.started = false
+	a.lifecycle.mu.Unlock()
+
+	stopped := make(chan struct{})
+	defer func() {
+		close(stopped)
+		a.cancelContext()
+	}()
+
+	// Abort the blocking calls of the services still running once the deadline passes
+	go func() {
+		select {
+		case <-ctx.Done():
+			a.cancelContext()
+		case <-stopped:
+		}
+	}()
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("[App] shutdown deadline exceeded before stopping %s: %w", created[i], err))
+			break
+		}
+
+		if err := a.runHooks(ctx, created[i], true); err != nil {
+			errs = append(errs, fmt.Errorf("[App] failed to stop %s: %w", created[i], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run starts the application and blocks until the context is cancelled or SIGINT/SIGTERM
+// is received, then shuts the application down within the configured shutdown timeout.
+func (a *App) Run(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+
+	signalCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-signalCtx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.opt.shutdownTimeout)
+	defer cancel()
+
+	return a.Shutdown(shutdownCtx)
+}
+
+// cancelContext cancels the root context of the application
+func (a *App) cancelContext() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// runHooks executes the start or stop hooks of a created singleton service
+func (a *App) runHooks(ctx context.Context, key Keywords, stopping bool) error {
+	instance, err := a.Get(key)
+	if err != nil {
+		return err
+	}
+
+	return a.runInstanceHooks(ctx, key, instance, stopping)
+}
+
+// runInstanceHooks executes the start or stop hooks registered for key against an instance
+func (a *App) runInstanceHooks(ctx context.Context, key Keywords, instance interface{}, stopping bool) error {
+	var list []Hook
+	if definition := a.lifecycle.definition(key); definition != nil {
+		list = definition.onStart
+		if stopping {
+			list = definition.onStop
+		}
+	}
+
+	// Fall back to the instance's own shutdown method when no stop hook is registered
+	if stopping && len(list) == 0 {
+		switch service := instance.(type) {
+		case interface{ Shutdown(context.Context) error }:
+			list = []Hook{func(ctx context.Context, _ interface{}) error { return service.Shutdown(ctx) }}
+		case interface{ Close() error }:
+			list = []Hook{func(context.Context, interface{}) error { return service.Close() }}
+		}
+	}
+
+	for _, hook := range list {
+		if err := runWithContext(ctx, func() error { return hook(ctx, instance) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runWithContext runs fn and stops waiting for it once the context is done
+func runWithContext(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// counters records how often a test service was created, started and stopped
+type counters struct {
+	created, started, stopped int
+}
+
+func (c *counters) hooks() []ServiceOption {
+	return []ServiceOption{
+		OnStart(func(ctx context.Context, instance interface{}) error {
+			c.started++
+			return nil
+		}),
+		OnStop(func(ctx context.Context, instance interface{}) error {
+			c.stopped++
+			return nil
+		}),
+	}
+}
+
+func TestStartRollsBackAndCanBeRetried(t *testing.T) {
+	tests := []struct {
+		name        string
+		failCreate  bool // The second service fails to be created on the first Start
+		failStart   bool // The second service fails to start on the first Start
+		wantStarted int  // Starts of the first service after the failed Start
+	}{
+		{name: "construction failure", failCreate: true, wantStarted: 0},
+		{name: "start hook failure", failStart: true, wantStarted: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := NewApp(Retry(RetryPolicy{}))
+			ctx := context.Background()
+
+			var first counters
+			if err := app.Use("first", func() (interface{}, error) {
+				first.created++
+				return &first, nil
+			}, first.hooks()...); err != nil {
+				t.Fatal(err)
+			}
+
+			attempts := 0
+			if err := app.Use("second", func() (interface{}, error) {
+				attempts++
+				if tt.failCreate && attempts == 1 {
+					return nil, errors.New("unavailable")
+				}
+				return attempts, nil
+			}, OnStart(func(ctx context.Context, instance interface{}) error {
+				if tt.failStart && instance == 1 {
+					return errors.New("cannot start")
+				}
+				return nil
+			}), DependsOn("first")); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := app.Start(ctx); err == nil {
+				t.Fatal("first Start succeeded, want an error")
+			}
+			// Only a started service is stopped by the rollback
+			if first.started != tt.wantStarted || first.stopped != tt.wantStarted {
+				t.Fatalf("after the failed Start: started %d, stopped %d, want %d each", first.started, first.stopped, tt.wantStarted)
+			}
+			if _, created := app.Instance("first"); created {
+				t.Fatal("first service still created after the rollback")
+			}
+			if err := app.GetContext().Err(); err != nil {
+				t.Fatalf("root context cancelled by the rollback: %v", err)
+			}
+
+			if err := app.Start(ctx); err != nil {
+				t.Fatalf("second Start: %v", err)
+			}
+			if first.created != 2 || first.started != tt.wantStarted+1 {
+				t.Fatalf("after the second Start: created %d, started %d", first.created, first.started)
+			}
+
+			if err := app.Shutdown(ctx); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestStartRollbackKeepsServicesCreatedBefore(t *testing.T) {
+	app := NewApp(Retry(RetryPolicy{}))
+	ctx := context.Background()
+
+	var early counters
+	if err := app.Use("early", func() (interface{}, error) {
+		early.created++
+		return &early, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Use("broken", func() (interface{}, error) {
+		return nil, errors.New("unavailable")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Get("early"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Start(ctx); err == nil {
+		t.Fatal("Start succeeded, want an error")
+	}
+
+	if _, created := app.Instance("early"); !created || early.created != 1 {
+		t.Fatalf("service created before Start was rolled back: created %d", early.created)
+	}
+	if app.lifecycle.isStarted() {
+		t.Fatal("application still marked as started")
+	}
+}
+
+// closer is a service stopped through its Close method
+type closer struct {
+	closed int
+}
+
+func (c *closer) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestStartRollbackStopsOnlyStartedServices(t *testing.T) {
+	app := NewApp(Retry(RetryPolicy{}))
+	ctx := context.Background()
+
+	var started, failing counters
+	unstarted := &closer{}
+	for _, service := range []struct {
+		key        Keywords
+		createFunc func() (interface{}, error)
+		opts       []ServiceOption
+	}{
+		{key: "started", createFunc: func() (interface{}, error) { return &started, nil }, opts: started.hooks()},
+		{key: "failing", createFunc: func() (interface{}, error) { return &failing, nil }, opts: append(failing.hooks(),
+			OnStart(func(ctx context.Context, instance interface{}) error { return errors.New("cannot start") }),
+			DependsOn("started"),
+		)},
+		{key: "unstarted", createFunc: func() (interface{}, error) { return unstarted, nil }, opts: []ServiceOption{DependsOn("failing")}},
+	} {
+		if err := app.Use(service.key, service.createFunc, service.opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.Start(ctx); err == nil {
+		t.Fatal("Start succeeded, want an error")
+	}
+
+	tests := []struct {
+		key         Keywords
+		stopped     int
+		wantStopped int
+	}{
+		{key: "started", stopped: started.stopped, wantStopped: 1},
+		// A service whose start hooks did not all succeed is not stopped
+		{key: "failing", stopped: failing.stopped, wantStopped: 0},
+		{key: "unstarted", stopped: unstarted.closed, wantStopped: 0},
+	}
+	for _, tt := range tests {
+		if tt.stopped != tt.wantStopped {
+			t.Errorf("%s stopped %d times, want %d", tt.key, tt.stopped, tt.wantStopped)
+		}
+		if _, created := app.Instance(tt.key); created {
+			t.Errorf("%s still created after the rollback", tt.key)
+		}
+	}
+}
+
+// recorder records the names of the stopped services in order
+type recorder struct {
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (r *recorder) stop(name string) Hook {
+	return func(ctx context.Context, instance interface{}) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.stopped = append(r.stopped, name)
+		return nil
+	}
+}
+
+func (r *recorder) names() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.stopped, ",")
+}
+
+func TestShutdownStopsInReverseOrder(t *testing.T) {
+	app := NewApp()
+	ctx := context.Background()
+
+	var stops recorder
+	var rootErr error
+	for _, service := range []struct {
+		key  Keywords
+		opts []ServiceOption
+	}{
+		// Registered out of dependency order, the services are created as database, cache, http
+		{key: "http", opts: []ServiceOption{DependsOn("cache"), OnStop(stops.stop("http"))}},
+		{key: "cache", opts: []ServiceOption{DependsOn("database"), OnStop(stops.stop("cache"))}},
+		{key: "database", opts: []ServiceOption{OnStop(func(ctx context.Context, instance interface{}) error {
+			rootErr = app.GetContext().Err()
+			return stops.stop("database")(ctx, instance)
+		})}},
+	} {
+		if err := app.Use(service.key, func() (interface{}, error) { return new(int), nil }, service.opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stops.names(); got != "http,cache,database" {
+		t.Fatalf("stopped %s, want http,cache,database", got)
+	}
+	if rootErr != nil {
+		t.Fatalf("root context cancelled before the last service stopped: %v", rootErr)
+	}
+	if err := app.GetContext().Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("root context error = %v after Shutdown, want %v", err, context.Canceled)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	app := NewApp()
+
+	var stops recorder
+	if err := app.Use("database", func() (interface{}, error) { return new(int), nil }, OnStop(stops.stop("database"))); err != nil {
+		t.Fatal(err)
+	}
+	// The stop hook of the worker blocks until the root context is cancelled
+	aborted := make(chan struct{})
+	if err := app.Use("worker", func() (interface{}, error) { return new(int), nil }, DependsOn("database"),
+		OnStop(func(ctx context.Context, instance interface{}) error {
+			<-app.GetContext().Done()
+			close(aborted)
+			return nil
+		}),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := app.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The blocked hook is released by the root context, the next services are not stopped
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("the root context was not cancelled once the deadline passed")
+	}
+	if got := stops.names(); got != "" {
+		t.Fatalf("stopped %s after the deadline", got)
+	}
+}
+
+func TestRunStopsWhenContextIsCancelled(t *testing.T) {
+	app := NewApp(ShutdownTimeout(time.Second))
+
+	var stops recorder
+	if err := app.Use("service", func() (interface{}, error) { return new(int), nil }, OnStop(stops.stop("service"))); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return once its context was cancelled")
+	}
+
+	if got := stops.names(); got != "service" {
+		t.Fatalf("stopped %q, want service", got)
+	}
+	if app.GetContext().Err() == nil {
+		t.Fatal("root context not cancelled after Run")
+	}
+}
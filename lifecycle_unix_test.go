@@ -0,0 +1,65 @@
+//go:build unix
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunStopsOnSignal(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal syscall.Signal
+	}{
+		{name: "SIGINT", signal: syscall.SIGINT},
+		{name: "SIGTERM", signal: syscall.SIGTERM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keep the signal from terminating the test binary before Run listens to it
+			ignored := make(chan os.Signal, 1)
+			signal.Notify(ignored, tt.signal)
+			defer signal.Stop(ignored)
+
+			app := NewApp(ShutdownTimeout(time.Second))
+			var stops recorder
+			if err := app.Use("service", func() (interface{}, error) { return new(int), nil }, OnStop(stops.stop("service"))); err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan error, 1)
+			go func() { done <- app.Run(context.Background()) }()
+
+			// The signal is sent until Run receives it, as Run listens once the app is started
+			deadline := time.After(time.Second)
+			for returned := false; !returned; {
+				select {
+				case err := <-done:
+					if err != nil {
+						t.Fatal(err)
+					}
+					returned = true
+				case <-time.After(10 * time.Millisecond):
+					if err := syscall.Kill(os.Getpid(), tt.signal); err != nil {
+						t.Fatal(err)
+					}
+				case <-deadline:
+					t.Fatal("Run did not return after the signal")
+				}
+			}
+
+			if got := stops.names(); got != "service" {
+				t.Fatalf("stopped %q, want service", got)
+			}
+			if app.GetContext().Err() == nil {
+				t.Fatal("root context not cancelled after Run")
+			}
+		})
+	}
+}
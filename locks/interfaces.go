@@ -0,0 +1,19 @@
+package locks
+
+import (
+	"context"
+	"time"
+)
+
+// LockDriver stores the locks. A lock is held by the owner token that acquired it until
+// it is released or its ttl passes.
+type LockDriver interface {
+	Name() string
+	Init(config interface{}) error
+	// TryAcquire takes the lock for the token unless it is held, and reports whether it was taken.
+	TryAcquire(ctx context.Context, key string, token string, ttl time.Duration) (bool, error)
+	// Release frees the lock held by the token, or returns locks_models.ErrNotOwner.
+	Release(ctx context.Context, key string, token string) error
+	// Extend resets the ttl of the lock held by the token, or returns locks_models.ErrNotOwner.
+	Extend(ctx context.Context, key string, token string, ttl time.Duration) error
+}
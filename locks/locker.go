@@ -0,0 +1,148 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HemendCo/go-core/health"
+	"github.com/HemendCo/go-core/locks/locks_models"
+	"github.com/google/uuid"
+)
+
+// DefaultRetryInterval is how often Acquire retries to take a held lock
+const DefaultRetryInterval = 100 * time.Millisecond
+
+var (
+	// ErrNotAcquired is returned by TryAcquire when the lock is held by another owner.
+	ErrNotAcquired = errors.New("lock is held by another owner")
+	// ErrNotOwner is returned when a lock is released or extended by an owner that does not hold it.
+	ErrNotOwner = locks_models.ErrNotOwner
+)
+
+// Locker acquires locks from a driver.
+type Locker struct {
+	driver        LockDriver
+	retryInterval time.Duration
+}
+
+// NewLocker creates a locker over the driver.
+func NewLocker(driver LockDriver) *Locker {
+	return &Locker{
+		driver:        driver,
+		retryInterval: DefaultRetryInterval,
+	}
+}
+
+// SetRetryInterval changes how often Acquire retries to take a held lock.
+func (l *Locker) SetRetryInterval(interval time.Duration) *Locker {
+	l.retryInterval = interval
+	return l
+}
+
+// Driver returns the driver of the locker.
+func (l *Locker) Driver() LockDriver {
+	return l.driver
+}
+
+// Health checks the driver of the locker, if it can be checked.
+func (l *Locker) Health(ctx context.Context) error {
+	if checker, ok := l.driver.(health.Checker); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// Close closes the driver of the locker, if it can be closed.
+func (l *Locker) Close() error {
+	if closer, ok := l.driver.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// TryAcquire takes the lock for ttl with a new owner token, or returns ErrNotAcquired if it is held.
+func (l *Locker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	token := uuid.New().String()
+
+	acquired, err := l.driver.TryAcquire(ctx, key, token, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !acquired {
+		return nil, ErrNotAcquired
+	}
+
+	return &Lock{locker: l, key: key, token: token}, nil
+}
+
+// Acquire takes the lock for ttl, waiting until it is released, expires or ctx is done.
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	ticker := time.NewTicker(l.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		lock, err := l.TryAcquire(ctx, key, ttl)
+		if !errors.Is(err, ErrNotAcquired) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", key, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release frees the lock held by the owner token, e.g. a token handed over to another process.
+func (l *Locker) Release(ctx context.Context, key string, token string) error {
+	return l.driver.Release(ctx, key, token)
+}
+
+// Extend resets the ttl of the lock held by the owner token.
+func (l *Locker) Extend(ctx context.Context, key string, token string, ttl time.Duration) error {
+	return l.driver.Extend(ctx, key, token, ttl)
+}
+
+// WithLock runs fn while holding the lock, waiting for it as Acquire does.
+func (l *Locker) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lock, err := l.Acquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	err = fn(ctx)
+	if releaseErr := lock.Release(context.WithoutCancel(ctx)); releaseErr != nil && !errors.Is(releaseErr, ErrNotOwner) {
+		err = errors.Join(err, releaseErr)
+	}
+	return err
+}
+
+// Lock is a lock held by its owner token.
+type Lock struct {
+	locker *Locker
+	key    string
+	token  string
+}
+
+// Key returns the key of the lock.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token returns the owner token of the lock.
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// Release frees the lock, or returns ErrNotOwner if it expired and was taken by another owner.
+func (l *Lock) Release(ctx context.Context) error {
+	return l.locker.Release(ctx, l.key, l.token)
+}
+
+// Extend resets the ttl of the lock, or returns ErrNotOwner if it expired and was taken by another owner.
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	return l.locker.Extend(ctx, l.key, l.token, ttl)
+}
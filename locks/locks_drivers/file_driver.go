@@ -0,0 +1,221 @@
+package locks_drivers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/HemendCo/go-core/helpers"
+	"github.com/HemendCo/go-core/locks/locks_models"
+)
+
+// mutexFile is the file of the lock directory whose advisory lock guards the lock files
+const mutexFile = ".mutex"
+
+// fileLock is the content of a lock file
+type fileLock struct {
+	Key        string    `json:"key"`
+	Token      string    `json:"token"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// FileLockDriver keeps the locks as files, shared by every process using the same directory.
+// A lock file whose ttl passed is stale and is taken over by the next owner. Lock files are
+// only read and changed under the advisory lock of the mutex file of the directory.
+type FileLockDriver struct {
+	cfg  *locks_models.FileLockConfig
+	path string
+	mu   sync.Mutex
+}
+
+// Name returns the name of the lock driver.
+func (f *FileLockDriver) Name() string {
+	return "file"
+}
+
+// Init initializes the file lock driver and creates its directory.
+func (f *FileLockDriver) Init(config interface{}) error {
+	cfg, ok := config.(locks_models.FileLockConfig)
+	if !ok {
+		return errors.New("invalid file lock configuration: expected a locks_models.FileLockConfig type")
+	}
+
+	path := helpers.JoinWithProjectPath(cfg.Path)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cfg = &cfg
+	f.path = path
+
+	return nil
+}
+
+// Health checks that the lock directory is writable.
+func (f *FileLockDriver) Health(ctx context.Context) error {
+	return helpers.CheckWritableDir(f.path)
+}
+
+// TryAcquire takes the lock for the token unless a lock file that is not stale exists.
+func (f *FileLockDriver) TryAcquire(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file := f.lockFile(key)
+	acquired := false
+	err := f.exclusive(func() error {
+		current, err := f.read(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err == nil && time.Now().Before(current.Expiration) {
+			return nil
+		}
+
+		// The lock is free or stale, it is taken over
+		if err := f.write(file, fileLock{Key: key, Token: token, Expiration: time.Now().Add(ttl)}); err != nil {
+			return err
+		}
+		acquired = true
+		return nil
+	})
+
+	return acquired, err
+}
+
+// Release frees the lock held by the token.
+func (f *FileLockDriver) Release(ctx context.Context, key string, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file := f.lockFile(key)
+	return f.exclusive(func() error {
+		if err := f.holds(file, token); err != nil {
+			return err
+		}
+
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+}
+
+// Extend resets the ttl of the lock held by the token.
+func (f *FileLockDriver) Extend(ctx context.Context, key string, token string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file := f.lockFile(key)
+	return f.exclusive(func() error {
+		if err := f.holds(file, token); err != nil {
+			return err
+		}
+
+		return f.write(file, fileLock{Key: key, Token: token, Expiration: time.Now().Add(ttl)})
+	})
+}
+
+// exclusive runs fn holding an advisory lock on the mutex file of the lock directory, so that
+// reading a lock file and replacing or removing it is atomic across processes
+func (f *FileLockDriver) exclusive(fn func() error) error {
+	mutex, err := os.OpenFile(filepath.Join(f.path, mutexFile), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer mutex.Close()
+
+	if err := lockMutex(mutex); err != nil {
+		return err
+	}
+	defer unlockMutex(mutex)
+
+	return fn()
+}
+
+// holds checks that the lock file is held by the token and not stale
+func (f *FileLockDriver) holds(file string, token string) error {
+	lock, err := f.read(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return locks_models.ErrNotOwner
+	}
+	if err != nil {
+		return err
+	}
+
+	if lock.Token != token || !time.Now().Before(lock.Expiration) {
+		return locks_models.ErrNotOwner
+	}
+
+	return nil
+}
+
+// write replaces the lock file; renaming a complete temporary file keeps other processes
+// from reading a partially written lock.
+func (f *FileLockDriver) write(file string, lock fileLock) error {
+	temp, err := f.writeTemp(lock)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(temp, file); err != nil {
+		_ = os.Remove(temp)
+		return err
+	}
+
+	return nil
+}
+
+// writeTemp writes the lock to a temporary file in the lock directory
+func (f *FileLockDriver) writeTemp(lock fileLock) (string, error) {
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return "", err
+	}
+
+	temp, err := os.CreateTemp(f.path, ".lock-*")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return "", err
+	}
+
+	return temp.Name(), nil
+}
+
+// read reads a lock file
+func (f *FileLockDriver) read(file string) (*fileLock, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock fileLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	return &lock, nil
+}
+
+// lockFile returns the lock file of the key
+func (f *FileLockDriver) lockFile(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(f.path, hex.EncodeToString(hash[:])+".lock")
+}
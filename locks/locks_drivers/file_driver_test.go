@@ -0,0 +1,202 @@
+package locks_drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/locks/locks_models"
+)
+
+// newFileDriver creates a file lock driver over dir, as another process would
+func newFileDriver(t *testing.T, dir string) *FileLockDriver {
+	t.Helper()
+
+	wd, _ := os.Getwd()
+	rel, err := filepath.Rel(wd, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	driver := &FileLockDriver{}
+	if err := driver.Init(locks_models.FileLockConfig{Path: rel}); err != nil {
+		t.Fatal(err)
+	}
+	return driver
+}
+
+func TestFileLockReleaseKeepsTakenOverLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	first, second := newFileDriver(t, dir), newFileDriver(t, dir)
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{name: "acquire", run: func() error { return acquire(first, "a", 10*time.Millisecond) }},
+		{name: "wait for expiry", run: func() error { time.Sleep(20 * time.Millisecond); return nil }},
+		{name: "take over", run: func() error { return acquire(second, "b", time.Minute) }},
+		{name: "release by the previous owner", run: func() error { return first.Release(ctx, "key", "a") }, wantErr: locks_models.ErrNotOwner},
+		{name: "held by the new owner", run: func() error { return second.Extend(ctx, "key", "b", time.Minute) }},
+		{name: "release by the new owner", run: func() error { return second.Release(ctx, "key", "b") }},
+		{name: "released", run: func() error { return second.Release(ctx, "key", "b") }, wantErr: locks_models.ErrNotOwner},
+	}
+
+	for _, tt := range tests {
+		if err := tt.run(); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	assertNoLockFiles(t, dir)
+}
+
+func TestFileLockConcurrentTakeoverAndRelease(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		driver := newFileDriver(t, dir)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				token := fmt.Sprintf("%d-%d", i, j)
+				acquired, err := driver.TryAcquire(ctx, "key", token, time.Millisecond)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !acquired {
+					continue
+				}
+
+				// Expired locks are taken over by the other owners while this one still releases it
+				time.Sleep(time.Millisecond)
+				if err := driver.Release(ctx, "key", token); err != nil && !errors.Is(err, locks_models.ErrNotOwner) {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every release either removed its own lock or left the lock of its successor
+	driver := newFileDriver(t, dir)
+	time.Sleep(2 * time.Millisecond)
+	if err := acquire(driver, "last", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Release(ctx, "key", "last"); err != nil {
+		t.Fatal(err)
+	}
+	assertNoLockFiles(t, dir)
+}
+
+func TestFileLockMutualExclusion(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	const ttl = 20 * time.Millisecond
+
+	// holder is the token holding the lock and the earliest time its lock expires
+	var (
+		mu        sync.Mutex
+		holder    string
+		expires   time.Time
+		takeovers atomic.Int32
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		driver := newFileDriver(t, dir)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				token := fmt.Sprintf("%d-%d", i, j)
+				before := time.Now()
+				acquired, err := driver.TryAcquire(ctx, "key", token, ttl)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !acquired {
+					time.Sleep(time.Millisecond)
+					continue
+				}
+
+				mu.Lock()
+				if holder != "" {
+					if time.Now().Before(expires) {
+						t.Errorf("%s acquired the lock held by %s", token, holder)
+					}
+					takeovers.Add(1)
+				}
+				holder, expires = token, before.Add(ttl)
+				mu.Unlock()
+
+				// Every fourth owner abandons its lock, which is taken over once stale
+				if j%4 == 3 {
+					continue
+				}
+
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				if holder == token {
+					holder = ""
+				}
+				mu.Unlock()
+				if err := driver.Release(ctx, "key", token); err != nil && !errors.Is(err, locks_models.ErrNotOwner) {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if takeovers.Load() == 0 {
+		t.Fatal("no stale lock was taken over")
+	}
+}
+
+// acquire takes the lock "key" for the token
+func acquire(driver *FileLockDriver, token string, ttl time.Duration) error {
+	acquired, err := driver.TryAcquire(context.Background(), "key", token, ttl)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return errors.New("lock not acquired")
+	}
+	return nil
+}
+
+// assertNoLockFiles fails if the lock directory holds other files than its mutex file
+func assertNoLockFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == mutexFile {
+			continue
+		}
+		t.Errorf("file %s left in the lock directory", entry.Name())
+	}
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package locks_drivers
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockMutex takes the exclusive advisory lock of the file, waiting for its current holder
+func lockMutex(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+// unlockMutex releases the advisory lock of the file
+func unlockMutex(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package locks_drivers
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockMutex takes the exclusive lock of the first byte of the file, waiting for its current holder
+func lockMutex(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, new(windows.Overlapped))
+}
+
+// unlockMutex releases the lock of the first byte of the file
+func unlockMutex(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
@@ -0,0 +1,88 @@
+package locks_drivers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/HemendCo/go-core/locks/locks_models"
+)
+
+// mapLock is a lock held in memory
+type mapLock struct {
+	token      string
+	expiration time.Time
+}
+
+// MapLockDriver keeps the locks in memory; they are only shared within the process, e.g. in tests.
+type MapLockDriver struct {
+	locks map[string]mapLock
+	mu    sync.Mutex
+}
+
+// Name returns the name of the lock driver.
+func (m *MapLockDriver) Name() string {
+	return "map"
+}
+
+// Init initializes the map lock driver.
+func (m *MapLockDriver) Init(config interface{}) error {
+	if _, ok := config.(locks_models.MapLockConfig); !ok {
+		return errors.New("invalid map lock configuration: expected a locks_models.MapLockConfig type")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.locks == nil {
+		m.locks = make(map[string]mapLock)
+	}
+
+	return nil
+}
+
+// TryAcquire takes the lock for the token unless it is held and not expired.
+func (m *MapLockDriver) TryAcquire(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if lock, exists := m.locks[key]; exists && time.Now().Before(lock.expiration) {
+		return false, nil
+	}
+
+	m.locks[key] = mapLock{token: token, expiration: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// Release frees the lock held by the token.
+func (m *MapLockDriver) Release(ctx context.Context, key string, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.holds(key, token) {
+		return locks_models.ErrNotOwner
+	}
+
+	delete(m.locks, key)
+	return nil
+}
+
+// Extend resets the ttl of the lock held by the token.
+func (m *MapLockDriver) Extend(ctx context.Context, key string, token string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.holds(key, token) {
+		return locks_models.ErrNotOwner
+	}
+
+	m.locks[key] = mapLock{token: token, expiration: time.Now().Add(ttl)}
+	return nil
+}
+
+// holds reports whether the token holds the unexpired lock; the caller must hold the mutex
+func (m *MapLockDriver) holds(key string, token string) bool {
+	lock, exists := m.locks[key]
+	return exists && lock.token == token && time.Now().Before(lock.expiration)
+}
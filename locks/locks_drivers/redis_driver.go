@@ -0,0 +1,136 @@
+package locks_drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HemendCo/go-core/locks/locks_models"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock only if it is held by the token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendScript resets the ttl of the lock only if it is held by the token
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RedisLockDriver keeps the locks in Redis, shared by every replica using the same server.
+type RedisLockDriver struct {
+	client *redis.Client
+	cfg    *locks_models.RedisLockConfig
+	mu     sync.RWMutex
+}
+
+// Name returns the name of the lock driver.
+func (r *RedisLockDriver) Name() string {
+	return "redis"
+}
+
+// Init connects to Redis with the provided configuration.
+func (r *RedisLockDriver) Init(config interface{}) error {
+	cfg, ok := config.(locks_models.RedisLockConfig)
+	if !ok {
+		return errors.New("invalid redis lock configuration: expected a locks_models.RedisLockConfig type")
+	}
+
+	if cfg.Prefix == "" {
+		cfg.Prefix = "lock:"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.client != nil && *r.cfg == cfg {
+		return nil
+	}
+
+	if r.client != nil {
+		if err := r.client.Close(); err != nil {
+			return err
+		}
+	}
+
+	r.cfg = &cfg
+	r.client = redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.Database,
+	})
+
+	return nil
+}
+
+// getClient returns the Redis client and the prefixed key of the lock.
+func (r *RedisLockDriver) getClient(key string) (*redis.Client, string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.client, r.cfg.Prefix + key
+}
+
+// TryAcquire takes the lock for the token with SET NX.
+func (r *RedisLockDriver) TryAcquire(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	client, key := r.getClient(key)
+	return client.SetNX(ctx, key, token, ttl).Result()
+}
+
+// Release frees the lock held by the token.
+func (r *RedisLockDriver) Release(ctx context.Context, key string, token string) error {
+	client, key := r.getClient(key)
+
+	deleted, err := releaseScript.Run(ctx, client, []string{key}, token).Int()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return locks_models.ErrNotOwner
+	}
+
+	return nil
+}
+
+// Extend resets the ttl of the lock held by the token.
+func (r *RedisLockDriver) Extend(ctx context.Context, key string, token string, ttl time.Duration) error {
+	client, key := r.getClient(key)
+
+	extended, err := extendScript.Run(ctx, client, []string{key}, token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return locks_models.ErrNotOwner
+	}
+
+	return nil
+}
+
+// Health pings the Redis server.
+func (r *RedisLockDriver) Health(ctx context.Context) error {
+	client, _ := r.getClient("")
+	return client.Ping(ctx).Err()
+}
+
+// Close closes the Redis client.
+func (r *RedisLockDriver) Close() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.client == nil {
+		return nil
+	}
+	return r.client.Close()
+}
@@ -0,0 +1,35 @@
+package locks_models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
+// ErrNotOwner is returned when a lock is released or extended with a token that does not hold it,
+// e.g. because the lock expired and was acquired by another owner.
+var ErrNotOwner = errors.New("lock is not held by this owner")
+
+type RedisLockConfig struct {
+	Host     string `mapstructure:"host" validate:"required"`
+	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Database int    `mapstructure:"database"`
+	Prefix   string `mapstructure:"prefix"` // Prefix of the lock keys, "lock:" by default
+}
+
+// String formats the configuration with the credentials redacted
+func (c RedisLockConfig) String() string {
+	type plain RedisLockConfig
+	c.Password = helpers.Redact(c.Password)
+	return fmt.Sprintf("%+v", plain(c))
+}
+
+type FileLockConfig struct {
+	Path string `mapstructure:"path" validate:"required"`
+}
+
+type MapLockConfig struct {
+}
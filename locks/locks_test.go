@@ -0,0 +1,241 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/locks/locks_models"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// driverCase creates a lock driver and lets its locks expire
+type driverCase struct {
+	name    string
+	driver  func(t *testing.T) LockDriver
+	advance func(d time.Duration) // Moves the clock of the driver forward
+}
+
+// driverCases returns the drivers under test; drivers created by the same case share their locks
+func driverCases(t *testing.T) []driverCase {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	host, port, _ := net.SplitHostPort(server.Addr())
+	dir := t.TempDir()
+	memory := &mapDriver{}
+
+	return []driverCase{
+		{
+			name:    "map",
+			driver:  func(t *testing.T) LockDriver { return memory.get(t) },
+			advance: time.Sleep,
+		},
+		{
+			name: "file",
+			driver: func(t *testing.T) LockDriver {
+				return newDriver(t, "file", locks_models.FileLockConfig{Path: relativePath(t, dir)})
+			},
+			advance: time.Sleep,
+		},
+		{
+			name: "redis",
+			driver: func(t *testing.T) LockDriver {
+				driver := newDriver(t, "redis", locks_models.RedisLockConfig{Host: host, Port: port})
+				t.Cleanup(func() { driver.(interface{ Close() error }).Close() })
+				return driver
+			},
+			advance: server.FastForward,
+		},
+	}
+}
+
+// mapDriver shares a single map driver between the lockers of a case
+type mapDriver struct {
+	once   sync.Once
+	driver LockDriver
+}
+
+func (m *mapDriver) get(t *testing.T) LockDriver {
+	m.once.Do(func() { m.driver = newDriver(t, "map", locks_models.MapLockConfig{}) })
+	return m.driver
+}
+
+func newDriver(t *testing.T, name string, config interface{}) LockDriver {
+	t.Helper()
+
+	driver, err := NewLocksManager().CreateLocksFactory(name, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return driver
+}
+
+// relativePath returns the path of dir relative to the project directory
+func relativePath(t *testing.T, dir string) string {
+	t.Helper()
+
+	wd, _ := os.Getwd()
+	rel, err := filepath.Rel(wd, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rel
+}
+
+func TestLocker(t *testing.T) {
+	ctx := context.Background()
+
+	for _, dc := range driverCases(t) {
+		t.Run(dc.name, func(t *testing.T) {
+			first := NewLocker(dc.driver(t))
+			second := NewLocker(dc.driver(t)) // Another process
+
+			lock, err := first.TryAcquire(ctx, "report", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := second.TryAcquire(ctx, "report", time.Minute); !errors.Is(err, ErrNotAcquired) {
+				t.Fatalf("TryAcquire of a held lock = %v, want ErrNotAcquired", err)
+			}
+
+			tests := []struct {
+				name string
+				err  error
+			}{
+				{name: "release by another owner", err: second.Release(ctx, "report", "other-token")},
+				{name: "extend by another owner", err: second.Extend(ctx, "report", "other-token", time.Minute)},
+				{name: "release of a missing lock", err: second.Release(ctx, "missing", lock.Token())},
+				{name: "extend of a missing lock", err: second.Extend(ctx, "missing", lock.Token(), time.Minute)},
+			}
+			for _, tt := range tests {
+				if !errors.Is(tt.err, ErrNotOwner) {
+					t.Errorf("%s = %v, want ErrNotOwner", tt.name, tt.err)
+				}
+			}
+
+			if err := lock.Extend(ctx, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			// The owner token can be handed over to another process
+			if err := second.Release(ctx, "report", lock.Token()); err != nil {
+				t.Fatal(err)
+			}
+			if err := lock.Release(ctx); !errors.Is(err, ErrNotOwner) {
+				t.Fatalf("second Release = %v, want ErrNotOwner", err)
+			}
+
+			relocked, err := second.TryAcquire(ctx, "report", time.Minute)
+			if err != nil {
+				t.Fatalf("TryAcquire of a released lock: %v", err)
+			}
+			if err := relocked.Release(ctx); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestLockExpiryAndTakeover(t *testing.T) {
+	ctx := context.Background()
+	ttl := 50 * time.Millisecond
+
+	for _, dc := range driverCases(t) {
+		t.Run(dc.name, func(t *testing.T) {
+			first := NewLocker(dc.driver(t))
+			second := NewLocker(dc.driver(t))
+
+			expired, err := first.TryAcquire(ctx, "job", ttl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dc.advance(2 * ttl)
+
+			taken, err := second.TryAcquire(ctx, "job", time.Minute)
+			if err != nil {
+				t.Fatalf("the expired lock was not taken over: %v", err)
+			}
+
+			// The previous owner can neither extend nor release the lock it lost
+			if err := expired.Extend(ctx, time.Minute); !errors.Is(err, ErrNotOwner) {
+				t.Fatalf("Extend by the previous owner = %v, want ErrNotOwner", err)
+			}
+			if err := expired.Release(ctx); !errors.Is(err, ErrNotOwner) {
+				t.Fatalf("Release by the previous owner = %v, want ErrNotOwner", err)
+			}
+			if _, err := first.TryAcquire(ctx, "job", time.Minute); !errors.Is(err, ErrNotAcquired) {
+				t.Fatalf("the lock of the new owner was released: %v", err)
+			}
+
+			if err := taken.Extend(ctx, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			if err := taken.Release(ctx); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestWithLockExcludesConcurrentRuns(t *testing.T) {
+	for _, dc := range driverCases(t) {
+		if dc.name == "redis" {
+			continue // Acquire retries in real time, which does not move the clock of the test server
+		}
+
+		t.Run(dc.name, func(t *testing.T) {
+			var running, maxRunning, runs atomic.Int32
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				locker := NewLocker(dc.driver(t)).SetRetryInterval(time.Millisecond)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					err := locker.WithLock(context.Background(), "critical", time.Minute, func(ctx context.Context) error {
+						current := running.Add(1)
+						defer running.Add(-1)
+						for {
+							previous := maxRunning.Load()
+							if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+								break
+							}
+						}
+						time.Sleep(2 * time.Millisecond)
+						runs.Add(1)
+						return nil
+					})
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if runs.Load() != 8 || maxRunning.Load() != 1 {
+				t.Fatalf("%d runs, up to %d at once; want 8 runs one at a time", runs.Load(), maxRunning.Load())
+			}
+		})
+	}
+}
+
+func TestAcquireStopsWithContext(t *testing.T) {
+	locker := NewLocker(newDriver(t, "map", locks_models.MapLockConfig{})).SetRetryInterval(time.Millisecond)
+
+	if _, err := locker.TryAcquire(context.Background(), "busy", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := locker.Acquire(ctx, "busy", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire of a held lock = %v, want the context error", err)
+	}
+}
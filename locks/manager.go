@@ -0,0 +1,42 @@
+package locks
+
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core/locks/locks_drivers"
+)
+
+type LocksManager struct {
+	drivers map[string]LockDriver
+}
+
+func NewLocksManager(drivers ...LockDriver) *LocksManager {
+	manager := &LocksManager{
+		drivers: make(map[string]LockDriver),
+	}
+
+	// register default driver
+	drivers = append(drivers, &locks_drivers.RedisLockDriver{}, &locks_drivers.FileLockDriver{}, &locks_drivers.MapLockDriver{})
+	manager.RegisterDrivers(drivers...)
+
+	return manager
+}
+
+func (lm *LocksManager) RegisterDrivers(drivers ...LockDriver) {
+	for _, driver := range drivers {
+		lm.drivers[driver.Name()] = driver
+	}
+}
+
+func (lm *LocksManager) CreateLocksFactory(driverName string, config interface{}) (LockDriver, error) {
+	driver, exists := lm.drivers[driverName]
+	if !exists {
+		return nil, fmt.Errorf("unsupported locks driver %s", driverName)
+	}
+
+	if err := driver.Init(config); err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
@@ -1,5 +1,5 @@
 package logger_models
 
 type FileLoggerConfig struct {
-	Filepath string
+	Filepath string `mapstructure:"filepath" validate:"required"`
 }
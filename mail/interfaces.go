@@ -0,0 +1,12 @@
+package mail
+
+import (
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/mail/mail_models"
+)
+
+type MailDriver interface {
+	Name() string
+	Init(app *core.App, config interface{}) error
+	Send(message *mail_models.Message) error
+}
@@ -0,0 +1,51 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// Job sends a queued message with the mail driver of the application.
+// Register it with the worker that processes the messages, e.g. worker.Run(&mail.Job{}).
+type Job struct {
+}
+
+func (j *Job) TypeName() string {
+	return "mail:send"
+}
+
+func (j *Job) NewTask(app *core.App, params interface{}) (interface{}, error) {
+	message, ok := params.(*mail_models.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected params type %T for job %s", params, j.TypeName())
+	}
+	return message, nil
+}
+
+func (j *Job) Handler(app *core.App, payload []byte) error {
+	var message mail_models.Message
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return fmt.Errorf("invalid payload for job %s: %w", j.TypeName(), err)
+	}
+
+	driver, err := core.Resolve[MailDriver](app, core.MailKeyword)
+	if err != nil {
+		return err
+	}
+
+	return driver.Send(&message)
+}
+
+// Queue sends the message in the background through the worker of the application.
+func Queue(app *core.App, message *mail_models.Message) error {
+	worker, err := core.Resolve[worker_interfaces.WorkerDriver](app, core.WorkerKeyword)
+	if err != nil {
+		return err
+	}
+
+	return worker.Enqueue(&Job{}, message)
+}
@@ -0,0 +1,209 @@
+package mail_drivers
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/mail/mail_models"
+)
+
+// smtpServer is a minimal SMTP server recording the conversations it accepts
+type smtpServer struct {
+	listener net.Listener
+	reject   string // A recipient refused by RCPT TO
+
+	mu         sync.Mutex
+	from       string
+	recipients []string
+	data       string
+}
+
+func newSMTPServer(t *testing.T, reject string) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener, reject: reject}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+
+		s.mu.Lock()
+		switch {
+		case verb == "EHLO" || verb == "HELO":
+			reply("250 localhost")
+		case strings.HasPrefix(strings.ToUpper(command), "MAIL FROM:"):
+			s.from = strings.Trim(command[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(strings.ToUpper(command), "RCPT TO:"):
+			recipient := strings.Trim(command[len("RCPT TO:"):], "<>")
+			if recipient == s.reject {
+				reply("550 mailbox unavailable")
+				break
+			}
+			s.recipients = append(s.recipients, recipient)
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("502 command not implemented")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestSMTPMailDriver(t *testing.T) {
+	tests := []struct {
+		name           string
+		reject         string
+		wantErr        bool
+		wantRecipients string
+	}{
+		{name: "delivered", wantRecipients: "ada@example.com,bob@example.com,audit@example.com"},
+		{name: "rejected recipient", reject: "bob@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSMTPServer(t, tt.reject)
+			host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+
+			driver := &SMTPMailDriver{}
+			if err := driver.Init(core.NewApp(), mail_models.SMTPMailConfig{
+				Host: host, Port: port, Encryption: "none", From: "noreply@example.com",
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := driver.Health(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			err := driver.Send(&mail_models.Message{
+				To:      []mail_models.Address{{Email: "ada@example.com"}},
+				Cc:      []mail_models.Address{{Email: "bob@example.com"}},
+				Bcc:     []mail_models.Address{{Email: "audit@example.com"}},
+				Subject: "Welcome",
+				Text:    "hello",
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if server.from != "noreply@example.com" {
+				t.Errorf("MAIL FROM %q, want the configured sender", server.from)
+			}
+			if got := strings.Join(server.recipients, ","); got != tt.wantRecipients {
+				t.Errorf("recipients = %s, want %s", got, tt.wantRecipients)
+			}
+			if !strings.Contains(server.data, "Subject: Welcome") || strings.Contains(server.data, "audit@example.com") {
+				t.Errorf("unexpected message:\n%s", server.data)
+			}
+		})
+	}
+}
+
+func TestSMTPMailDriverInit(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   interface{}
+		wantPort string
+		wantErr  bool
+	}{
+		{name: "starttls port", config: mail_models.SMTPMailConfig{Host: "smtp"}, wantPort: "587"},
+		{name: "tls port", config: mail_models.SMTPMailConfig{Host: "smtp", Encryption: "tls"}, wantPort: "465"},
+		{name: "explicit port", config: mail_models.SMTPMailConfig{Host: "smtp", Port: "2525"}, wantPort: "2525"},
+		{name: "invalid encryption", config: mail_models.SMTPMailConfig{Host: "smtp", Encryption: "ssl"}, wantErr: true},
+		{name: "invalid config", config: mail_models.LogMailConfig{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		driver := &SMTPMailDriver{}
+		err := driver.Init(core.NewApp(), tt.config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Init() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && driver.cfg.Port != tt.wantPort {
+			t.Errorf("%s: port = %s, want %s", tt.name, driver.cfg.Port, tt.wantPort)
+		}
+	}
+}
+
+func TestLogMailDriver(t *testing.T) {
+	// The path is relative to the project directory
+	wd, _ := os.Getwd()
+	dir, err := filepath.Rel(wd, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	driver := &LogMailDriver{}
+	if err := driver.Init(core.NewApp(), mail_models.LogMailConfig{Path: dir, From: "noreply@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Send(&mail_models.Message{To: []mail_models.Address{{Email: "ada@example.com"}}, Subject: "Logged"}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(wd, dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("%d files written, want 1", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(data), "From: <noreply@example.com>") {
+		t.Fatalf("the configured sender is missing:\n%s", data)
+	}
+}
@@ -0,0 +1,58 @@
+package mail_drivers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/helpers"
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/google/uuid"
+)
+
+// LogMailDriver writes every message to an .eml file instead of sending it, for development.
+type LogMailDriver struct {
+	app  *core.App
+	cfg  mail_models.LogMailConfig
+	path string
+}
+
+func (l *LogMailDriver) Name() string {
+	return "log"
+}
+
+func (l *LogMailDriver) Init(app *core.App, config interface{}) error {
+	cfg, ok := config.(mail_models.LogMailConfig)
+	if !ok {
+		return errors.New("invalid log mail configuration: expected a mail_models.LogMailConfig type")
+	}
+
+	l.app = app
+	l.cfg = cfg
+	l.path = helpers.JoinWithProjectPath(cfg.Path)
+
+	return os.MkdirAll(l.path, os.ModePerm)
+}
+
+// Send writes the message to <path>/<time>_<id>.eml
+func (l *LogMailDriver) Send(message *mail_models.Message) error {
+	message = withSender(message, l.cfg.From, l.cfg.FromName)
+
+	now := time.Now()
+	data, err := BuildMIME(message, now)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(l.path, fmt.Sprintf("%s_%s.eml", now.Format("2006-01-02T15-04-05"), uuid.New().String()))
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	log.Printf("[Mail] %q to %v written to %s", message.Subject, message.Recipients(), file)
+	return nil
+}
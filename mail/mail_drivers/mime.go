@@ -0,0 +1,218 @@
+package mail_drivers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/google/uuid"
+)
+
+// BuildMIME encodes a message as a MIME document. Bcc recipients are left out of the headers.
+// The body is multipart/alternative when the message has a text and an HTML body, inline
+// attachments are related to the HTML body and other attachments make it multipart/mixed.
+func BuildMIME(message *mail_models.Message, date time.Time) ([]byte, error) {
+	if message.From.Email == "" {
+		return nil, errors.New("mail message has no sender")
+	}
+	if len(message.Recipients()) == 0 {
+		return nil, errors.New("mail message has no recipient")
+	}
+
+	header, body := messageBody(message)
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", message.From.String())
+	writeHeader(&buf, "To", joinAddresses(message.To))
+	writeHeader(&buf, "Cc", joinAddresses(message.Cc))
+	writeHeader(&buf, "Reply-To", joinAddresses(message.ReplyTo))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	writeHeader(&buf, "Date", date.Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(message.From.Email))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	names := make([]string, 0, len(message.Headers))
+	for name := range message.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(&buf, textproto.CanonicalMIMEHeaderKey(name), mime.QEncoding.Encode("utf-8", message.Headers[name]))
+	}
+
+	writeEntity(&buf, header, body)
+	return buf.Bytes(), nil
+}
+
+// messageBody returns the MIME entity of the bodies and attachments
+func messageBody(message *mail_models.Message) (textproto.MIMEHeader, []byte) {
+	var inline, attached []mail_models.Attachment
+	for _, attachment := range message.Attachments {
+		if attachment.Inline && message.HTML != "" {
+			inline = append(inline, attachment)
+		} else {
+			attached = append(attached, attachment)
+		}
+	}
+
+	var alternatives []entity
+	if message.Text != "" || message.HTML == "" {
+		alternatives = append(alternatives, textEntity("text/plain", message.Text))
+	}
+	if message.HTML != "" {
+		html := textEntity("text/html", message.HTML)
+		if len(inline) > 0 {
+			related := []entity{html}
+			for _, attachment := range inline {
+				related = append(related, attachmentEntity(attachment))
+			}
+			html = multipartEntity("related", related)
+		}
+		alternatives = append(alternatives, html)
+	}
+
+	body := alternatives[0]
+	if len(alternatives) > 1 {
+		body = multipartEntity("alternative", alternatives)
+	}
+
+	if len(attached) > 0 {
+		mixed := []entity{body}
+		for _, attachment := range attached {
+			mixed = append(mixed, attachmentEntity(attachment))
+		}
+		body = multipartEntity("mixed", mixed)
+	}
+
+	return body.header, body.body
+}
+
+// entity is a MIME part
+type entity struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+func textEntity(contentType string, content string) entity {
+	var buf bytes.Buffer
+	writer := quotedprintable.NewWriter(&buf)
+	writer.Write([]byte(content))
+	writer.Close()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	return entity{header: header, body: buf.Bytes()}
+}
+
+func attachmentEntity(attachment mail_models.Attachment) entity {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	if attachment.Inline {
+		disposition = "inline"
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	if attachment.ContentID != "" {
+		header.Set("Content-ID", "<"+attachment.ContentID+">")
+	}
+
+	return entity{header: header, body: wrapBase64(attachment.Data)}
+}
+
+func multipartEntity(subtype string, parts []entity) entity {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, part := range parts {
+		partWriter, _ := writer.CreatePart(part.header)
+		partWriter.Write(part.body)
+	}
+	writer.Close()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", fmt.Sprintf("multipart/%s; boundary=%q", subtype, writer.Boundary()))
+	return entity{header: header, body: buf.Bytes()}
+}
+
+// writeEntity writes the headers of an entity, a blank line and its body
+func writeEntity(buf *bytes.Buffer, header textproto.MIMEHeader, body []byte) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(buf, name, header.Get(name))
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+}
+
+// writeHeader writes a header line, skipping empty values
+func writeHeader(buf *bytes.Buffer, name string, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(buf, "%s: %s\r\n", name, value)
+}
+
+// wrapBase64 encodes data in base64 lines of 76 characters
+func wrapBase64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	return buf.Bytes()
+}
+
+func joinAddresses(addresses []mail_models.Address) string {
+	formatted := make([]string, len(addresses))
+	for i, address := range addresses {
+		formatted[i] = address.String()
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// messageID returns a unique Message-ID in the domain of the sender
+func messageID(from string) string {
+	domain := "localhost"
+	if _, host, ok := strings.Cut(from, "@"); ok && host != "" {
+		domain = host
+	}
+	return fmt.Sprintf("<%s@%s>", uuid.New().String(), domain)
+}
+
+// withSender returns the message with the configured sender if it has none
+func withSender(message *mail_models.Message, email string, name string) *mail_models.Message {
+	if message.From.Email != "" {
+		return message
+	}
+
+	res := *message
+	res.From = mail_models.Address{Name: name, Email: email}
+	return &res
+}
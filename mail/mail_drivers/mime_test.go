@@ -0,0 +1,173 @@
+package mail_drivers
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core/mail/mail_models"
+)
+
+// part is the content type of a MIME entity and its parts
+type part struct {
+	contentType string
+	parts       []part
+	body        string // The decoded body of a leaf entity
+}
+
+// parseEntity parses a MIME entity into its tree of parts
+func parseEntity(t *testing.T, contentType string, body io.Reader) part {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := part{contentType: mediaType}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.body = string(data)
+		return res
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			return res
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.parts = append(res.parts, parseEntity(t, p.Header.Get("Content-Type"), p))
+	}
+}
+
+// structure formats the content types of the tree, e.g. "multipart/alternative(text/plain,text/html)"
+func (p part) structure() string {
+	if len(p.parts) == 0 {
+		return p.contentType
+	}
+	children := make([]string, len(p.parts))
+	for i, child := range p.parts {
+		children[i] = child.structure()
+	}
+	return p.contentType + "(" + strings.Join(children, ",") + ")"
+}
+
+func TestBuildMIME(t *testing.T) {
+	logo := mail_models.Attachment{Filename: "logo.png", Data: []byte{0x89, 'P', 'N', 'G'}, Inline: true, ContentID: "logo"}
+	report := mail_models.Attachment{Filename: "report.pdf", Data: bytes.Repeat([]byte("x"), 200)}
+
+	tests := []struct {
+		name        string
+		text        string
+		html        string
+		attachments []mail_models.Attachment
+		want        string
+	}{
+		{name: "text", text: "hello", want: "text/plain"},
+		{name: "empty body", want: "text/plain"},
+		{name: "html", html: "<p>hello</p>", want: "text/html"},
+		{name: "alternative", text: "hello", html: "<p>hello</p>", want: "multipart/alternative(text/plain,text/html)"},
+		{name: "inline image", html: `<img src="cid:logo">`, attachments: []mail_models.Attachment{logo}, want: "multipart/related(text/html,image/png)"},
+		{name: "inline without html is attached", text: "hello", attachments: []mail_models.Attachment{logo}, want: "multipart/mixed(text/plain,image/png)"},
+		{
+			name:        "everything",
+			text:        "hello",
+			html:        `<img src="cid:logo">`,
+			attachments: []mail_models.Attachment{logo, report},
+			want:        "multipart/mixed(multipart/alternative(text/plain,multipart/related(text/html,image/png)),application/pdf)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := &mail_models.Message{
+				From:        mail_models.Address{Name: "App", Email: "noreply@example.com"},
+				To:          []mail_models.Address{{Name: "Ada", Email: "ada@example.com"}},
+				Bcc:         []mail_models.Address{{Email: "audit@example.com"}},
+				Subject:     "Héllo",
+				Text:        tt.text,
+				HTML:        tt.html,
+				Attachments: tt.attachments,
+				Headers:     map[string]string{"x-campaign": "welcome"},
+			}
+
+			data, err := BuildMIME(message, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := mail.ReadMessage(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			headers := map[string]string{
+				"From":       parsed.Header.Get("From"),
+				"To":         parsed.Header.Get("To"),
+				"Subject":    subject,
+				"X-Campaign": parsed.Header.Get("X-Campaign"),
+				"Bcc":        parsed.Header.Get("Bcc"),
+				"Message-Id": strings.TrimSuffix(parsed.Header.Get("Message-Id"), "@example.com>"),
+			}
+			wantHeaders := map[string]string{
+				"From":       `"App" <noreply@example.com>`,
+				"To":         `"Ada" <ada@example.com>`,
+				"Subject":    "Héllo",
+				"X-Campaign": "welcome",
+				"Bcc":        "",
+			}
+			for name, want := range wantHeaders {
+				if headers[name] != want {
+					t.Errorf("header %s = %q, want %q", name, headers[name], want)
+				}
+			}
+			if headers["Message-Id"] == parsed.Header.Get("Message-Id") {
+				t.Errorf("Message-ID %q is not in the domain of the sender", parsed.Header.Get("Message-Id"))
+			}
+
+			if got := parseEntity(t, parsed.Header.Get("Content-Type"), parsed.Body).structure(); got != tt.want {
+				t.Errorf("structure = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMIMERequiresAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *mail_models.Message
+	}{
+		{name: "no sender", message: &mail_models.Message{To: []mail_models.Address{{Email: "ada@example.com"}}}},
+		{name: "no recipient", message: &mail_models.Message{From: mail_models.Address{Email: "noreply@example.com"}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := BuildMIME(tt.message, time.Now()); err == nil {
+			t.Errorf("%s: BuildMIME succeeded", tt.name)
+		}
+	}
+}
+
+func TestWrapBase64(t *testing.T) {
+	for _, size := range []int{0, 1, 57, 58, 300} {
+		lines := strings.Split(string(wrapBase64(bytes.Repeat([]byte("a"), size))), "\r\n")
+		for _, line := range lines {
+			if len(line) > 76 {
+				t.Errorf("size %d: line of %d characters", size, len(line))
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package mail_drivers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/mail/mail_models"
+)
+
+// SMTPMailDriver sends messages through an SMTP server.
+type SMTPMailDriver struct {
+	app *core.App
+	cfg mail_models.SMTPMailConfig
+}
+
+func (s *SMTPMailDriver) Name() string {
+	return "smtp"
+}
+
+func (s *SMTPMailDriver) Init(app *core.App, config interface{}) error {
+	cfg, ok := config.(mail_models.SMTPMailConfig)
+	if !ok {
+		return errors.New("invalid smtp mail configuration: expected a mail_models.SMTPMailConfig type")
+	}
+
+	switch cfg.Encryption {
+	case "", "tls", "starttls", "none":
+	default:
+		return fmt.Errorf("invalid smtp encryption %q: expected tls, starttls or none", cfg.Encryption)
+	}
+
+	if cfg.Port == "" {
+		cfg.Port = "587"
+		if cfg.Encryption == "tls" {
+			cfg.Port = "465"
+		}
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	s.app = app
+	s.cfg = cfg
+
+	return nil
+}
+
+// Send delivers the message to the recipients
+func (s *SMTPMailDriver) Send(message *mail_models.Message) error {
+	message = withSender(message, s.cfg.From, s.cfg.FromName)
+
+	data, err := BuildMIME(message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(s.app.GetContext(), s.cfg.Timeout)
+	defer cancel()
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Abort the conversation when the application shuts down or the timeout passes
+	stop := context.AfterFunc(ctx, func() {
+		client.Close()
+	})
+	defer stop()
+
+	if err := client.Mail(message.From.Email); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, recipient := range message.Recipients() {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// Health checks that the SMTP server accepts connections and credentials
+func (s *SMTPMailDriver) Health(ctx context.Context) error {
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Quit()
+}
+
+// dial connects to the server, negotiates TLS and authenticates
+func (s *SMTPMailDriver) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+
+	var conn net.Conn
+	var err error
+	if s.cfg.Encryption == "tls" {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server %s: %w", addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to smtp server %s: %w", addr, err)
+	}
+
+	if s.cfg.Encryption == "" || s.cfg.Encryption == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("smtp STARTTLS failed: %w", err)
+			}
+		} else if s.cfg.Encryption == "starttls" {
+			client.Close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+	}
+
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	return client, nil
+}
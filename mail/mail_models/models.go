@@ -0,0 +1,78 @@
+package mail_models
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
+// Address is a mailbox, formatted as "Name <email>"
+type Address struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email"`
+}
+
+func (a Address) String() string {
+	return (&mail.Address{Name: a.Name, Address: a.Email}).String()
+}
+
+// Attachment is a file attached to a message. Inline attachments are referenced
+// from the HTML body by their content id, e.g. <img src="cid:logo">.
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"` // Detected from the filename if empty
+	Data        []byte `json:"data"`
+	Inline      bool   `json:"inline,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+// Message is an email with a text body, an HTML body or both.
+type Message struct {
+	From        Address           `json:"from"` // The configured sender if empty
+	To          []Address         `json:"to"`
+	Cc          []Address         `json:"cc,omitempty"`
+	Bcc         []Address         `json:"bcc,omitempty"`
+	ReplyTo     []Address         `json:"reply_to,omitempty"`
+	Subject     string            `json:"subject"`
+	Text        string            `json:"text,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// Recipients returns the email addresses of the To, Cc and Bcc recipients
+func (m *Message) Recipients() []string {
+	var recipients []string
+	for _, list := range [][]Address{m.To, m.Cc, m.Bcc} {
+		for _, address := range list {
+			recipients = append(recipients, address.Email)
+		}
+	}
+	return recipients
+}
+
+type SMTPMailConfig struct {
+	Host       string        `mapstructure:"host" validate:"required"`
+	Port       string        `mapstructure:"port"`
+	Username   string        `mapstructure:"username"`
+	Password   string        `mapstructure:"password"`
+	Encryption string        `mapstructure:"encryption"` // "tls", "starttls" or "none"; "starttls" when the server offers it by default
+	Timeout    time.Duration `mapstructure:"timeout"`
+	From       string        `mapstructure:"from"`
+	FromName   string        `mapstructure:"from_name"`
+}
+
+// String formats the configuration with the credentials redacted
+func (c SMTPMailConfig) String() string {
+	type plain SMTPMailConfig
+	c.Password = helpers.Redact(c.Password)
+	return fmt.Sprintf("%+v", plain(c))
+}
+
+type LogMailConfig struct {
+	Path     string `mapstructure:"path" validate:"required"`
+	From     string `mapstructure:"from"`
+	FromName string `mapstructure:"from_name"`
+}
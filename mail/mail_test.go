@@ -0,0 +1,127 @@
+package mail
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// memoryDriver is a mail driver keeping the sent messages
+type memoryDriver struct {
+	sent []*mail_models.Message
+}
+
+func (d *memoryDriver) Name() string                                 { return "memory" }
+func (d *memoryDriver) Init(app *core.App, config interface{}) error { return nil }
+
+func (d *memoryDriver) Send(message *mail_models.Message) error {
+	d.sent = append(d.sent, message)
+	return nil
+}
+
+// queueWorker is a worker keeping the payloads of the enqueued jobs
+type queueWorker struct {
+	payloads [][]byte
+}
+
+func (w *queueWorker) Name() string                                          { return "queue" }
+func (w *queueWorker) Init(app *core.App, config interface{}) error          { return nil }
+func (w *queueWorker) RegisterJobHandlers(handlers ...worker_interfaces.Job) {}
+func (w *queueWorker) JobHandlerExists(handler worker_interfaces.Job) bool   { return true }
+func (w *queueWorker) Close() error                                          { return nil }
+func (w *queueWorker) Run(handlers ...worker_interfaces.Job) error           { return nil }
+
+func (w *queueWorker) Enqueue(job worker_interfaces.Job, params interface{}) error {
+	task, err := job.NewTask(nil, params)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	w.payloads = append(w.payloads, payload)
+	return nil
+}
+
+func TestTemplatesRender(t *testing.T) {
+	templates, err := NewTemplates(fstest.MapFS{
+		"layout.html":  {Data: []byte(`{{define "layout"}}<main>{{template "content" .}}</main>{{end}}`)},
+		"welcome.html": {Data: []byte(`{{template "layout" .}}{{define "content"}}Hi {{.Name}}{{end}}`)},
+		"welcome.txt":  {Data: []byte(`Hi {{.Name}}`)},
+		"reset.txt":    {Data: []byte(`Reset: {{.Link}}`)},
+		"ignored.md":   {Data: []byte(`# not a template`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		wantHTML string
+		wantText string
+		wantErr  bool
+	}{
+		{name: "welcome", data: map[string]string{"Name": "<Ada>"}, wantHTML: "<main>Hi &lt;Ada&gt;</main>", wantText: "Hi <Ada>"},
+		{name: "reset", data: map[string]string{"Link": "https://example.com/?a=1&b=2"}, wantText: "Reset: https://example.com/?a=1&b=2"},
+		{name: "ignored", wantErr: true},
+		{name: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message mail_models.Message
+			err := templates.Render(&message, tt.name, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, want error %v", err, tt.wantErr)
+			}
+			if message.HTML != tt.wantHTML || message.Text != tt.wantText {
+				t.Fatalf("rendered %q and %q, want %q and %q", message.HTML, message.Text, tt.wantHTML, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestQueue(t *testing.T) {
+	app := core.NewApp()
+	driver := &memoryDriver{}
+	worker := &queueWorker{}
+	if err := app.Use(core.MailKeyword, func() (interface{}, error) { return driver, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Use(core.WorkerKeyword, func() (interface{}, error) { return worker, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	message := &mail_models.Message{
+		To:          []mail_models.Address{{Email: "ada@example.com"}},
+		Subject:     "Queued",
+		Attachments: []mail_models.Attachment{{Filename: "a.bin", Data: []byte{0, 1, 2}}},
+	}
+	if err := Queue(app, message); err != nil {
+		t.Fatal(err)
+	}
+	if len(driver.sent) != 0 || len(worker.payloads) != 1 {
+		t.Fatalf("%d sent and %d queued, want 0 and 1", len(driver.sent), len(worker.payloads))
+	}
+
+	job := &Job{}
+	if err := job.Handler(app, worker.payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(driver.sent) != 1 || driver.sent[0].Subject != "Queued" || string(driver.sent[0].Attachments[0].Data) != "\x00\x01\x02" {
+		t.Fatalf("sent %+v, want the queued message", driver.sent)
+	}
+
+	if _, err := job.NewTask(app, *message); err == nil {
+		t.Fatal("NewTask accepted a message that is not a pointer")
+	}
+	if err := job.Handler(app, []byte("{")); err == nil {
+		t.Fatal("Handler accepted an invalid payload")
+	}
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/mail/mail_drivers"
+)
+
+type MailManager struct {
+	app     *core.App
+	drivers map[string]MailDriver
+}
+
+func NewMailManager(app *core.App) *MailManager {
+	manager := &MailManager{
+		app:     app,
+		drivers: make(map[string]MailDriver),
+	}
+
+	// register default driver
+	manager.RegisterDriver(&mail_drivers.SMTPMailDriver{})
+	manager.RegisterDriver(&mail_drivers.LogMailDriver{})
+
+	return manager
+}
+
+func (mm *MailManager) RegisterDriver(driver MailDriver) {
+	mm.drivers[driver.Name()] = driver
+}
+
+func (mm *MailManager) CreateMailFactory(driverName string, config interface{}) (MailDriver, error) {
+	driver, exists := mm.drivers[driverName]
+	if !exists {
+		return nil, fmt.Errorf("unsupported mail driver %s", driverName)
+	}
+
+	if err := driver.Init(mm.app, config); err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	texttemplate "text/template"
+
+	"github.com/HemendCo/go-core/mail/mail_models"
+)
+
+// Templates renders message bodies from template files: "<name>.html" for the HTML body
+// and "<name>.txt" for the text body. Either file may be omitted. Templates can share
+// definitions, e.g. a layout, through the {{define}} and {{template}} actions.
+type Templates struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// NewTemplates parses the *.html and *.txt files of the directory of fsys, e.g. os.DirFS("resources/mail").
+func NewTemplates(fsys fs.FS) (*Templates, error) {
+	t := &Templates{}
+
+	if files, err := fs.Glob(fsys, "*.html"); err != nil {
+		return nil, err
+	} else if len(files) > 0 {
+		if t.html, err = htmltemplate.ParseFS(fsys, files...); err != nil {
+			return nil, fmt.Errorf("failed to parse mail templates: %w", err)
+		}
+	}
+
+	if files, err := fs.Glob(fsys, "*.txt"); err != nil {
+		return nil, err
+	} else if len(files) > 0 {
+		if t.text, err = texttemplate.ParseFS(fsys, files...); err != nil {
+			return nil, fmt.Errorf("failed to parse mail templates: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// Render sets the bodies of the message from the templates of the name executed with data.
+func (t *Templates) Render(message *mail_models.Message, name string, data interface{}) error {
+	found := false
+
+	if t.html != nil && t.html.Lookup(name+".html") != nil {
+		var buf bytes.Buffer
+		if err := t.html.ExecuteTemplate(&buf, name+".html", data); err != nil {
+			return fmt.Errorf("failed to render mail template %s.html: %w", name, err)
+		}
+		message.HTML = buf.String()
+		found = true
+	}
+
+	if t.text != nil && t.text.Lookup(name+".txt") != nil {
+		var buf bytes.Buffer
+		if err := t.text.ExecuteTemplate(&buf, name+".txt", data); err != nil {
+			return fmt.Errorf("failed to render mail template %s.txt: %w", name, err)
+		}
+		message.Text = buf.String()
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("mail template %s not found", name)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/database"
+	"github.com/HemendCo/go-core/mail"
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/HemendCo/go-core/sms"
+	"github.com/HemendCo/go-core/sms/sms_models"
+	"github.com/google/uuid"
+)
+
+// SMSMessage is a rendered sms notification
+type SMSMessage struct {
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// SMSChannel sends notifications with the sms driver of the application.
+type SMSChannel struct {
+	app *core.App
+}
+
+func (c *SMSChannel) Name() string {
+	return SMSChannelName
+}
+
+func (c *SMSChannel) Message(notifiable Notifiable, notification Notification) (interface{}, error) {
+	to, _ := notifiable.RouteNotificationFor(SMSChannelName).(string)
+	if to == "" {
+		return nil, nil
+	}
+
+	renderer, ok := notification.(SMSNotification)
+	if !ok {
+		return nil, fmt.Errorf("notification %T does not implement ToSMS", notification)
+	}
+
+	text, err := renderer.ToSMS(notifiable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMSMessage{To: to, Text: text}, nil
+}
+
+func (c *SMSChannel) NewMessage() interface{} {
+	return &SMSMessage{}
+}
+
+func (c *SMSChannel) Deliver(ctx context.Context, message interface{}) error {
+	msg, ok := message.(*SMSMessage)
+	if !ok {
+		return fmt.Errorf("unexpected sms message type %T", message)
+	}
+
+	driver, err := core.Resolve[sms.SMSDriver](c.app, core.SMSKeyword)
+	if err != nil {
+		return err
+	}
+
+	res, err := driver.SendMessage(msg.To, msg.Text, nil)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != sms_models.OkStatusCode {
+		return errors.New(sms_models.ErrorMessages[res.StatusCode])
+	}
+	return nil
+}
+
+// MailNotification renders a notification for the mail channel. A nil message is not sent.
+type MailNotification interface {
+	ToMail(notifiable Notifiable) (*mail_models.Message, error)
+}
+
+// MailChannel sends notifications with the mail driver of the application.
+// The route of the notifiable is a mail_models.Address or an email string.
+type MailChannel struct {
+	app *core.App
+}
+
+func (c *MailChannel) Name() string {
+	return MailChannelName
+}
+
+func (c *MailChannel) Message(notifiable Notifiable, notification Notification) (interface{}, error) {
+	var to mail_models.Address
+	switch route := notifiable.RouteNotificationFor(MailChannelName).(type) {
+	case mail_models.Address:
+		to = route
+	case string:
+		to = mail_models.Address{Email: route}
+	}
+	if to.Email == "" {
+		return nil, nil
+	}
+
+	renderer, ok := notification.(MailNotification)
+	if !ok {
+		return nil, fmt.Errorf("notification %T does not implement ToMail", notification)
+	}
+
+	message, err := renderer.ToMail(notifiable)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, nil
+	}
+	if len(message.To) == 0 {
+		message.To = []mail_models.Address{to}
+	}
+
+	return message, nil
+}
+
+func (c *MailChannel) NewMessage() interface{} {
+	return &mail_models.Message{}
+}
+
+func (c *MailChannel) Deliver(ctx context.Context, message interface{}) error {
+	msg, ok := message.(*mail_models.Message)
+	if !ok {
+		return fmt.Errorf("unexpected mail message type %T", message)
+	}
+
+	driver, err := core.Resolve[mail.MailDriver](c.app, core.MailKeyword)
+	if err != nil {
+		return err
+	}
+
+	return driver.Send(msg)
+}
+
+// DatabaseNotification renders a notification for the database channel.
+type DatabaseNotification interface {
+	ToDatabase(notifiable Notifiable) (map[string]interface{}, error)
+}
+
+// NotificationType is implemented by notifications to name their type in the database,
+// the Go type name by default.
+type NotificationType interface {
+	NotificationType() string
+}
+
+// DatabaseChannel stores notifications in the notifications table of the database of the
+// application, see StoredNotification. The route of the notifiable is its id as a string.
+type DatabaseChannel struct {
+	app *core.App
+}
+
+func (c *DatabaseChannel) Name() string {
+	return DatabaseChannelName
+}
+
+func (c *DatabaseChannel) Message(notifiable Notifiable, notification Notification) (interface{}, error) {
+	id := fmt.Sprint(notifiable.RouteNotificationFor(DatabaseChannelName))
+	if id == "" || id == "<nil>" {
+		return nil, nil
+	}
+
+	renderer, ok := notification.(DatabaseNotification)
+	if !ok {
+		return nil, fmt.Errorf("notification %T does not implement ToDatabase", notification)
+	}
+
+	data, err := renderer.ToDatabase(notifiable)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationType := typeName(notification)
+	if named, ok := notification.(NotificationType); ok {
+		notificationType = named.NotificationType()
+	}
+
+	return &StoredNotification{
+		ID:             uuid.New().String(),
+		Type:           notificationType,
+		NotifiableType: typeName(notifiable),
+		NotifiableID:   id,
+		Data:           string(encoded),
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+func (c *DatabaseChannel) NewMessage() interface{} {
+	return &StoredNotification{}
+}
+
+func (c *DatabaseChannel) Deliver(ctx context.Context, message interface{}) error {
+	record, ok := message.(*StoredNotification)
+	if !ok {
+		return fmt.Errorf("unexpected database message type %T", message)
+	}
+
+	db, err := core.Resolve[*database.DB](c.app, core.DatabaseKeyword)
+	if err != nil {
+		return err
+	}
+
+	return db.GetConnectionForModel(record).DB().WithContext(ctx).Create(record).Error
+}
+
+// typeName returns the name of the type of a value, without pointer
+func typeName(value interface{}) string {
+	return reflect.Indirect(reflect.ValueOf(value)).Type().String()
+}
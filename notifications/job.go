@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/HemendCo/go-core"
+)
+
+// queuedMessage is the payload of the job delivering a queued notification
+type queuedMessage struct {
+	Channel string          `json:"channel"`
+	Message json.RawMessage `json:"message"`
+}
+
+// Job delivers queued notifications. Register it with the worker that processes
+// the notifications, e.g. worker.Run(notifier.Job()).
+type Job struct {
+	notifier *Notifier
+}
+
+// Job returns the worker job of the notifier.
+func (n *Notifier) Job() *Job {
+	return &Job{notifier: n}
+}
+
+func (j *Job) TypeName() string {
+	return "notifications:deliver"
+}
+
+func (j *Job) NewTask(app *core.App, params interface{}) (interface{}, error) {
+	message, ok := params.(queuedMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected params type %T for job %s", params, j.TypeName())
+	}
+	return message, nil
+}
+
+func (j *Job) Handler(app *core.App, payload []byte) error {
+	var queued queuedMessage
+	if err := json.Unmarshal(payload, &queued); err != nil {
+		return fmt.Errorf("invalid payload for job %s: %w", j.TypeName(), err)
+	}
+
+	channel, err := j.notifier.Channel(queued.Channel)
+	if err != nil {
+		return err
+	}
+
+	message := channel.NewMessage()
+	if err := json.Unmarshal(queued.Message, message); err != nil {
+		return fmt.Errorf("failed to decode queued %s notification: %w", queued.Channel, err)
+	}
+
+	return channel.Deliver(app.GetContext(), message)
+}
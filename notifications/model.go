@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StoredNotification is a notification stored by the database channel.
+// Create its table with db.AutoMigrate(&notifications.StoredNotification{}).
+type StoredNotification struct {
+	ID             string     `gorm:"primaryKey;size:36" json:"id"`
+	Type           string     `gorm:"size:255;index" json:"type"`
+	NotifiableType string     `gorm:"size:255;index:idx_notifications_notifiable" json:"notifiable_type"`
+	NotifiableID   string     `gorm:"size:64;index:idx_notifications_notifiable" json:"notifiable_id"`
+	Data           string     `gorm:"type:text" json:"data"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (StoredNotification) TableName() string {
+	return "notifications"
+}
+
+// Decode decodes the data of the notification into target
+func (n *StoredNotification) Decode(target interface{}) error {
+	return json.Unmarshal([]byte(n.Data), target)
+}
+
+// Unread returns the unread notifications of a notifiable, newest first
+func Unread(db *gorm.DB, notifiableType string, notifiableID string) ([]StoredNotification, error) {
+	var res []StoredNotification
+	err := db.Where("notifiable_type = ? AND notifiable_id = ? AND read_at IS NULL", notifiableType, notifiableID).
+		Order("created_at DESC").
+		Find(&res).Error
+	return res, err
+}
+
+// MarkAsRead marks the notifications of the ids as read
+func MarkAsRead(db *gorm.DB, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Model(&StoredNotification{}).Where("id IN ? AND read_at IS NULL", ids).Update("read_at", time.Now()).Error
+}
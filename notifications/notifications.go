@@ -0,0 +1,156 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// Built-in channel names
+const (
+	SMSChannelName      = "sms"
+	MailChannelName     = "mail"
+	DatabaseChannelName = "database"
+)
+
+// Notifiable receives notifications, e.g. a user.
+type Notifiable interface {
+	// RouteNotificationFor returns the destination of the notifiable on a channel:
+	// the mobile number (string) for "sms", a mail_models.Address for "mail" and
+	// the notifiable id (string) for "database". Nil skips the channel.
+	RouteNotificationFor(channel string) interface{}
+}
+
+// Notification is sent to notifiables through the channels it declares. It implements
+// the rendering of each declared channel: SMSNotification, MailNotification, DatabaseNotification
+// or the interface expected by a custom channel.
+type Notification interface {
+	Via(notifiable Notifiable) []string
+}
+
+// SMSNotification renders a notification for the sms channel.
+type SMSNotification interface {
+	ToSMS(notifiable Notifiable) (string, error)
+}
+
+// ShouldQueue is implemented by notifications that Send delivers in the background.
+type ShouldQueue interface {
+	ShouldQueue() bool
+}
+
+// Channel delivers notifications, e.g. by SMS.
+type Channel interface {
+	Name() string
+	// Message renders the notification for the notifiable; a nil message skips the notifiable.
+	// Messages are encoded as JSON when the notification is queued.
+	Message(notifiable Notifiable, notification Notification) (interface{}, error)
+	// NewMessage returns a pointer to decode a queued message into.
+	NewMessage() interface{}
+	// Deliver sends a message returned by Message or decoded into NewMessage.
+	Deliver(ctx context.Context, message interface{}) error
+}
+
+// Notifier sends notifications through the registered channels.
+type Notifier struct {
+	app      *core.App
+	mu       sync.RWMutex
+	channels map[string]Channel
+}
+
+// NewNotifier creates a notifier with the sms, mail and database channels, which
+// deliver through the services of the app.
+func NewNotifier(app *core.App) *Notifier {
+	notifier := &Notifier{
+		app:      app,
+		channels: make(map[string]Channel),
+	}
+
+	// register default channel
+	notifier.RegisterChannel(&SMSChannel{app: app})
+	notifier.RegisterChannel(&MailChannel{app: app})
+	notifier.RegisterChannel(&DatabaseChannel{app: app})
+
+	return notifier
+}
+
+// RegisterChannel adds a channel, replacing any channel of the same name.
+func (n *Notifier) RegisterChannel(channel Channel) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.channels[channel.Name()] = channel
+}
+
+// Channel returns the channel of the name.
+func (n *Notifier) Channel(name string) (Channel, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	channel, ok := n.channels[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported notification channel %s", name)
+	}
+	return channel, nil
+}
+
+// Send delivers the notification to the notifiables through its channels, or queues it
+// if it implements ShouldQueue. A failing channel does not prevent the other channels.
+func (n *Notifier) Send(ctx context.Context, notification Notification, notifiables ...Notifiable) error {
+	if queued, ok := notification.(ShouldQueue); ok && queued.ShouldQueue() {
+		return n.Queue(notification, notifiables...)
+	}
+
+	return n.each(notification, notifiables, func(channel Channel, message interface{}) error {
+		return channel.Deliver(ctx, message)
+	})
+}
+
+// Queue renders the notification now and delivers it in the background through the worker
+// of the application, one task per channel and notifiable.
+func (n *Notifier) Queue(notification Notification, notifiables ...Notifiable) error {
+	worker, err := core.Resolve[worker_interfaces.WorkerDriver](n.app, core.WorkerKeyword)
+	if err != nil {
+		return err
+	}
+
+	return n.each(notification, notifiables, func(channel Channel, message interface{}) error {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		return worker.Enqueue(n.Job(), queuedMessage{Channel: channel.Name(), Message: data})
+	})
+}
+
+// each renders the notification for every notifiable and channel and hands the messages to fn
+func (n *Notifier) each(notification Notification, notifiables []Notifiable, fn func(channel Channel, message interface{}) error) error {
+	var errs []error
+	for _, notifiable := range notifiables {
+		for _, name := range notification.Via(notifiable) {
+			channel, err := n.Channel(name)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			message, err := channel.Message(notifiable, notification)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to render notification for channel %s: %w", name, err))
+				continue
+			}
+			if message == nil {
+				continue
+			}
+
+			if err := fn(channel, message); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send notification through channel %s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,277 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/database"
+	"github.com/HemendCo/go-core/database/db_config"
+	"github.com/HemendCo/go-core/database/db_interfaces"
+	"github.com/HemendCo/go-core/mail/mail_models"
+	"github.com/HemendCo/go-core/sms/sms_models"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// user is the notifiable of the tests
+type user struct {
+	ID     string
+	Mobile string
+	Email  string
+}
+
+func (u *user) RouteNotificationFor(channel string) interface{} {
+	switch channel {
+	case SMSChannelName:
+		return u.Mobile
+	case MailChannelName:
+		return mail_models.Address{Name: u.ID, Email: u.Email}
+	case DatabaseChannelName:
+		return u.ID
+	}
+	return nil
+}
+
+// welcome is a notification sent through the channels of its field
+type welcome struct {
+	channels []string
+	queue    bool
+}
+
+func (n *welcome) Via(notifiable Notifiable) []string { return n.channels }
+func (n *welcome) ShouldQueue() bool                  { return n.queue }
+
+func (n *welcome) ToSMS(notifiable Notifiable) (string, error) {
+	return "Welcome " + notifiable.(*user).ID, nil
+}
+
+func (n *welcome) ToMail(notifiable Notifiable) (*mail_models.Message, error) {
+	return &mail_models.Message{Subject: "Welcome " + notifiable.(*user).ID}, nil
+}
+
+func (n *welcome) ToDatabase(notifiable Notifiable) (map[string]interface{}, error) {
+	return map[string]interface{}{"user": notifiable.(*user).ID}, nil
+}
+
+// smsOnly is a notification rendering only sms messages
+type smsOnly struct{}
+
+func (n *smsOnly) Via(notifiable Notifiable) []string {
+	return []string{SMSChannelName, MailChannelName}
+}
+func (n *smsOnly) ToSMS(notifiable Notifiable) (string, error) {
+	return "", errors.New("rendering failed")
+}
+
+// noMail is a notification rendering no mail message
+type noMail struct {
+	queue bool
+}
+
+func (n *noMail) Via(notifiable Notifiable) []string { return []string{MailChannelName} }
+func (n *noMail) ShouldQueue() bool                  { return n.queue }
+
+func (n *noMail) ToMail(notifiable Notifiable) (*mail_models.Message, error) {
+	return nil, nil
+}
+
+// smsDriver records the sent sms messages and answers with status
+type smsDriver struct {
+	status sms_models.StatusCode
+	sent   []string
+}
+
+func (d *smsDriver) Name() string                                 { return "memory" }
+func (d *smsDriver) Init(app *core.App, config interface{}) error { return nil }
+
+func (d *smsDriver) SendMessage(mobileNumber string, message string, sendDateTime *time.Time) (*sms_models.SMSResponse, error) {
+	d.sent = append(d.sent, mobileNumber+": "+message)
+	return &sms_models.SMSResponse{StatusCode: d.status}, nil
+}
+
+// mailDriver records the subjects and recipients of the sent mail
+type mailDriver struct {
+	sent []string
+}
+
+func (d *mailDriver) Name() string                                 { return "memory" }
+func (d *mailDriver) Init(app *core.App, config interface{}) error { return nil }
+
+func (d *mailDriver) Send(message *mail_models.Message) error {
+	d.sent = append(d.sent, message.To[0].Email+": "+message.Subject)
+	return nil
+}
+
+// queueWorker is a worker keeping the payloads of the enqueued jobs
+type queueWorker struct {
+	payloads [][]byte
+}
+
+func (w *queueWorker) Name() string                                          { return "queue" }
+func (w *queueWorker) Init(app *core.App, config interface{}) error          { return nil }
+func (w *queueWorker) RegisterJobHandlers(handlers ...worker_interfaces.Job) {}
+func (w *queueWorker) JobHandlerExists(handler worker_interfaces.Job) bool   { return true }
+func (w *queueWorker) Close() error                                          { return nil }
+func (w *queueWorker) Run(handlers ...worker_interfaces.Job) error           { return nil }
+
+func (w *queueWorker) Enqueue(job worker_interfaces.Job, params interface{}) error {
+	task, err := job.NewTask(nil, params)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	w.payloads = append(w.payloads, payload)
+	return nil
+}
+
+// services are the drivers of a test application
+type services struct {
+	app    *core.App
+	sms    *smsDriver
+	mail   *mailDriver
+	worker *queueWorker
+}
+
+func newServices(t *testing.T, smsStatus sms_models.StatusCode) *services {
+	t.Helper()
+
+	s := &services{app: core.NewApp(), sms: &smsDriver{status: smsStatus}, mail: &mailDriver{}, worker: &queueWorker{}}
+	for key, service := range map[core.Keywords]interface{}{
+		core.SMSKeyword:    s.sms,
+		core.MailKeyword:   s.mail,
+		core.WorkerKeyword: s.worker,
+	} {
+		if err := s.app.Use(key, func() (interface{}, error) { return service, nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestSend(t *testing.T) {
+	users := []Notifiable{
+		&user{ID: "ada", Mobile: "0912", Email: "ada@example.com"},
+		&user{ID: "bob"}, // No routes, skipped by every channel
+	}
+
+	tests := []struct {
+		name         string
+		notification Notification
+		smsStatus    sms_models.StatusCode
+		wantSMS      string
+		wantMail     string
+		wantErr      string
+	}{
+		{name: "sms and mail", notification: &welcome{channels: []string{SMSChannelName, MailChannelName}}, smsStatus: sms_models.OkStatusCode, wantSMS: "0912: Welcome ada", wantMail: "ada@example.com: Welcome ada"},
+		{name: "sms failure does not stop mail", notification: &welcome{channels: []string{SMSChannelName, MailChannelName}}, smsStatus: sms_models.MobileInvalidStatusCode, wantSMS: "0912: Welcome ada", wantMail: "ada@example.com: Welcome ada", wantErr: "mobile number is invalid"},
+		{name: "unknown channel", notification: &welcome{channels: []string{"slack", MailChannelName}}, wantMail: "ada@example.com: Welcome ada", wantErr: "unsupported notification channel slack"},
+		{name: "rendering failures", notification: &smsOnly{}, smsStatus: sms_models.OkStatusCode, wantErr: "does not implement ToMail"},
+		{name: "no mail message", notification: &noMail{}},
+		{name: "no queued mail message", notification: &noMail{queue: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServices(t, tt.smsStatus)
+
+			err := NewNotifier(s.app).Send(context.Background(), tt.notification, users...)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Send() error = %v, want %q", err, tt.wantErr)
+			}
+			if got := strings.Join(s.sms.sent, ";"); got != tt.wantSMS {
+				t.Errorf("sms = %q, want %q", got, tt.wantSMS)
+			}
+			if got := strings.Join(s.mail.sent, ";"); got != tt.wantMail {
+				t.Errorf("mail = %q, want %q", got, tt.wantMail)
+			}
+			if len(s.worker.payloads) != 0 {
+				t.Errorf("%d messages queued, want none", len(s.worker.payloads))
+			}
+		})
+	}
+}
+
+func TestQueuedNotifications(t *testing.T) {
+	s := newServices(t, sms_models.OkStatusCode)
+	notifier := NewNotifier(s.app)
+
+	err := notifier.Send(context.Background(), &welcome{channels: []string{SMSChannelName, MailChannelName}, queue: true}, &user{ID: "ada", Mobile: "0912", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.sms.sent) != 0 || len(s.mail.sent) != 0 || len(s.worker.payloads) != 2 {
+		t.Fatalf("%d sms and %d mail sent, %d queued; want 0, 0 and 2", len(s.sms.sent), len(s.mail.sent), len(s.worker.payloads))
+	}
+
+	for _, payload := range s.worker.payloads {
+		if err := notifier.Job().Handler(s.app, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.sms.sent) != 1 || s.sms.sent[0] != "0912: Welcome ada" || len(s.mail.sent) != 1 || s.mail.sent[0] != "ada@example.com: Welcome ada" {
+		t.Fatalf("delivered %v and %v", s.sms.sent, s.mail.sent)
+	}
+
+	if err := notifier.Job().Handler(s.app, []byte(`{"channel":"slack","message":{}}`)); err == nil {
+		t.Fatal("a message of an unknown channel was delivered")
+	}
+}
+
+func TestDatabaseChannel(t *testing.T) {
+	conn, err := database.NewDatabaseManager().CreateDatabaseFactory("main", db_config.DBConfig{
+		Driver:              "sqlite",
+		Database:            filepath.Join(t.TempDir(), "app.db"),
+		IsDefaultConnection: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := database.NewDB(nil, map[string]db_interfaces.DatabaseConnection{"main": conn})
+	defer db.Close()
+	if err := db.DB().AutoMigrate(&StoredNotification{}); err != nil {
+		t.Fatal(err)
+	}
+
+	app := core.NewApp()
+	if err := app.Use(core.DatabaseKeyword, func() (interface{}, error) { return db, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	notification := &welcome{channels: []string{DatabaseChannelName}}
+	ada := &user{ID: "ada"}
+	for i := 0; i < 2; i++ {
+		if err := NewNotifier(app).Send(context.Background(), notification, ada, &user{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unread, err := Unread(db.DB(), "notifications.user", "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unread) != 2 || unread[0].Type != "notifications.welcome" {
+		t.Fatalf("unread = %+v, want 2 welcome notifications", unread)
+	}
+
+	var data struct {
+		User string `json:"user"`
+	}
+	if err := unread[0].Decode(&data); err != nil || data.User != "ada" {
+		t.Fatalf("data = %+v, %v; want the user ada", data, err)
+	}
+
+	if err := MarkAsRead(db.DB(), unread[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if unread, _ = Unread(db.DB(), "notifications.user", "ada"); len(unread) != 1 {
+		t.Fatalf("%d unread after marking one as read, want 1", len(unread))
+	}
+}
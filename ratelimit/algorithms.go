@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// The algorithms work on integer microseconds so that their state reads the same in Go and in
+// the Redis scripts, whose numbers are doubles.
+
+// step is the outcome of applying an algorithm to the state of a key
+type step struct {
+	state      []int64
+	ttl        int64 // Microseconds to keep the state
+	allowed    bool
+	remaining  int64
+	retryAfter int64
+	resetAfter int64
+}
+
+// apply runs the algorithm on the state at now; reserve takes the requests even if they are denied
+func apply(algorithm Algorithm, state []int64, now int64, limit Limit, n int64, reserve bool) step {
+	switch algorithm {
+	case FixedWindow:
+		return fixedWindow(state, now, limit, n, reserve)
+	case SlidingWindow:
+		return slidingWindow(state, now, limit, n, reserve)
+	default:
+		return tokenBucket(state, now, limit, n, reserve)
+	}
+}
+
+// tokenBucket implements the bucket as a generic cell rate algorithm: the state is the
+// theoretical arrival time at which the bucket is full again.
+func tokenBucket(state []int64, now int64, limit Limit, n int64, reserve bool) step {
+	interval := float64(limit.Period.Microseconds()) / float64(limit.Rate)
+	capacity := float64(limit.capacity(TokenBucket))
+
+	tat := float64(now)
+	if len(state) == 1 && float64(state[0]) > tat {
+		tat = float64(state[0])
+	}
+
+	newTat := tat + float64(n)*interval
+	allowAt := newTat - capacity*interval
+
+	if float64(now) >= allowAt {
+		return step{
+			state:      []int64{int64(newTat)},
+			ttl:        int64(math.Ceil(newTat)) - now,
+			allowed:    true,
+			remaining:  int64(math.Floor((float64(now) - allowAt) / interval)),
+			resetAfter: int64(math.Ceil(newTat)) - now,
+		}
+	}
+
+	retryAfter := int64(math.Ceil(allowAt)) - now
+	if reserve {
+		return step{
+			state:      []int64{int64(newTat)},
+			ttl:        int64(math.Ceil(newTat)) - now,
+			retryAfter: retryAfter,
+			resetAfter: int64(math.Ceil(newTat)) - now,
+		}
+	}
+
+	return step{
+		state:      []int64{int64(tat)},
+		ttl:        int64(math.Ceil(tat)) - now,
+		remaining:  max(0, int64(math.Floor((float64(now)-(tat-capacity*interval))/interval))),
+		retryAfter: retryAfter,
+		resetAfter: int64(math.Ceil(tat)) - now,
+	}
+}
+
+// fixedWindow counts the requests of the current window; the state is the window start and
+// the count, which carries reserved requests over to the following windows.
+func fixedWindow(state []int64, now int64, limit Limit, n int64, reserve bool) step {
+	period := limit.Period.Microseconds()
+	rate := int64(limit.Rate)
+
+	start, count := now-now%period, int64(0)
+	if len(state) == 2 {
+		start, count = state[0], state[1]
+		if elapsed := (now - start) / period; elapsed > 0 {
+			start += elapsed * period
+			count = max(0, count-elapsed*rate)
+		}
+	}
+
+	res := step{allowed: count+n <= rate}
+	if res.allowed {
+		count += n
+	} else {
+		// The requests go to the window of the last counted requests if they fit, or else to the next one
+		window := (count - 1) / rate
+		if count+n > (window+1)*rate {
+			window++
+		}
+		res.retryAfter = start + window*period - now
+
+		if reserve {
+			count = max(count, window*rate) + n
+		}
+	}
+
+	windows := max(1, (count+rate-1)/rate)
+	res.state = []int64{start, count}
+	res.ttl = start + windows*period - now
+	res.remaining = max(0, rate-count)
+	res.resetAfter = res.ttl
+	return res
+}
+
+// slidingWindow estimates the requests of the last period from the counts of the current and
+// the previous window, weighting the previous one by its overlap with the last period. The state is
+// the window start, the count of the previous window and the count from the window start on, which
+// carries reserved requests over to the following windows, Rate requests per window.
+func slidingWindow(state []int64, now int64, limit Limit, n int64, reserve bool) step {
+	period := limit.Period.Microseconds()
+	rate := int64(limit.Rate)
+
+	start, previous, current := now-now%period, int64(0), int64(0)
+	if len(state) == 3 {
+		start, previous, current = state[0], state[1], state[2]
+		if elapsed := (now - start) / period; elapsed > 0 {
+			start += elapsed * period
+			previous = min(rate, max(0, current-(elapsed-1)*rate))
+			current = max(0, current-elapsed*rate)
+		}
+	}
+
+	weight := float64(period-(now-start)) / float64(period)
+	estimate := float64(previous)*weight + float64(current)
+
+	res := step{allowed: estimate+float64(n) <= float64(rate)}
+	if res.allowed {
+		current += n
+		res.remaining = int64(math.Floor(float64(rate) - estimate - float64(n)))
+	} else {
+		// The requests go to the window of the last counted requests if they fit before it ends,
+		// or else to the next one; they are allowed once the window before fades out enough
+		last := int64(0)
+		if current > 0 {
+			last = (current - 1) / rate
+		}
+		window, count, before := last, current-last*rate+n, previous
+		if last > 0 {
+			before = rate
+		}
+		for count > rate || count == rate && before > 0 {
+			window, count, before = window+1, n, count-n
+		}
+
+		at := start + window*period
+		if fade := count + before - rate; fade > 0 {
+			at += (fade*period + before - 1) / before
+		}
+		res.retryAfter = max(0, at-now)
+
+		if reserve {
+			current = window*rate + count
+		}
+	}
+
+	// The state is kept until the window after the last counted requests ends
+	windows := int64(1)
+	if current > 0 {
+		windows = (current-1)/rate + 2
+	}
+	res.state = []int64{start, previous, current}
+	res.ttl = start + max(2, windows)*period - now
+	res.resetAfter = start + windows*period - now
+	return res
+}
+
+// result converts the step to a result
+func (s step) result() Result {
+	return Result{
+		Allowed:    s.allowed,
+		Remaining:  int(s.remaining),
+		RetryAfter: time.Duration(s.retryAfter) * time.Microsecond,
+		ResetAfter: time.Duration(s.resetAfter) * time.Microsecond,
+	}
+}
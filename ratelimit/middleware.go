@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	corehttp "github.com/HemendCo/go-core/http"
+)
+
+// KeyFunc returns the key a request is limited by, e.g. the client address or an API key.
+type KeyFunc func(c *corehttp.Context) string
+
+// ByIP limits the requests per client address.
+func ByIP(c *corehttp.Context) string {
+	host, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		return c.Request.RemoteAddr
+	}
+	return host
+}
+
+// Middleware limits the requests per key; denied requests are answered with 429 Too Many Requests.
+// The X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset and Retry-After headers
+// describe the limit to the client.
+func Middleware(limiter *Limiter, key KeyFunc) corehttp.Middleware {
+	return func(next corehttp.HandlerFunc) corehttp.HandlerFunc {
+		return func(c *corehttp.Context) error {
+			result, err := limiter.Allow(c.Context(), key(c))
+			if err != nil {
+				return err
+			}
+
+			header := c.Writer.Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("X-RateLimit-Reset", seconds(result.ResetAfter))
+
+			if !result.Allowed {
+				header.Set("Retry-After", seconds(result.RetryAfter))
+				return corehttp.NewError(http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// seconds formats the duration in whole seconds, rounded up
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
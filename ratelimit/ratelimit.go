@@ -0,0 +1,261 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/cache"
+)
+
+// Algorithm selects how the requests of a key are counted.
+type Algorithm string
+
+const (
+	// TokenBucket refills Rate tokens per Period up to Burst tokens, allowing short bursts.
+	TokenBucket Algorithm = "token_bucket"
+	// FixedWindow allows Rate requests per window of Period, windows start at multiples of Period.
+	FixedWindow Algorithm = "fixed_window"
+	// SlidingWindow allows Rate requests per Period, weighting the previous window by its overlap.
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// ErrExceedsLimit is returned when more requests are taken at once than the limit ever allows.
+var ErrExceedsLimit = errors.New("[RateLimit] requests exceed the limit")
+
+// Limit is the number of requests allowed per period.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int // Capacity of the token bucket, Rate by default
+}
+
+// PerSecond returns a limit of rate requests per second.
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+// PerMinute returns a limit of rate requests per minute.
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+// PerHour returns a limit of rate requests per hour.
+func PerHour(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Hour}
+}
+
+// capacity returns the most requests that can be taken at once
+func (l Limit) capacity(algorithm Algorithm) int {
+	if algorithm == TokenBucket && l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// Result describes the state of a key after taking requests.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // Requests that can still be taken now
+	RetryAfter time.Duration // Time until the denied requests would be allowed
+	ResetAfter time.Duration // Time until the limit is fully available again
+}
+
+// Reservation is a request taken ahead of time; the caller should wait Delay before acting.
+type Reservation struct {
+	Result
+	Delay time.Duration
+}
+
+// Wait blocks until the reserved requests may be acted upon, or ctx is done.
+func (r *Reservation) Wait(ctx context.Context) error {
+	if r.Delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(r.Delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type Option interface {
+}
+
+// option holds the settings of a Limiter
+type option struct {
+	algorithm Algorithm
+	driver    cache.CacheDriver
+	prefix    string
+}
+
+// Internal option representations.
+type (
+	algorithmOption Algorithm
+	storeOption     struct{ driver cache.CacheDriver }
+	prefixOption    string
+)
+
+// Using returns an option to specify the algorithm, TokenBucket by default.
+func Using(algorithm Algorithm) Option {
+	return algorithmOption(algorithm)
+}
+
+// Store returns an option to keep the counters in the given cache driver instead of the cache service.
+func Store(driver cache.CacheDriver) Option {
+	return storeOption{driver: driver}
+}
+
+// Prefix returns an option to specify the prefix of the cache keys, "ratelimit_<name>_" by default.
+func Prefix(prefix string) Option {
+	return prefixOption(prefix)
+}
+
+func composeOptions(name string, opts ...Option) option {
+	res := option{
+		algorithm: TokenBucket,
+		prefix:    "ratelimit_" + name + "_",
+	}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case algorithmOption:
+			res.algorithm = Algorithm(opt)
+		case storeOption:
+			res.driver = opt.driver
+		case prefixOption:
+			res.prefix = string(opt)
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
+
+// Limiter limits the requests per key, e.g. per mobile number or per client.
+type Limiter struct {
+	name  string
+	limit Limit
+	opt   option
+	store store
+}
+
+// NewLimiter creates a named limiter whose counters live in the cache service of the app.
+// The cache driver must be atomic: redis, or map and file within a single process.
+func NewLimiter(app *core.App, name string, limit Limit, opts ...Option) (*Limiter, error) {
+	opt := composeOptions(name, opts...)
+
+	if limit.Rate <= 0 || limit.Period <= 0 || limit.Burst < 0 {
+		return nil, fmt.Errorf("[RateLimit] invalid limit %+v for %s", limit, name)
+	}
+
+	switch opt.algorithm {
+	case TokenBucket, FixedWindow, SlidingWindow:
+	default:
+		return nil, fmt.Errorf("[RateLimit] unsupported algorithm %s for %s", opt.algorithm, name)
+	}
+
+	driver := opt.driver
+	if driver == nil {
+		var err error
+		if driver, err = core.Resolve[cache.CacheDriver](app, core.CacheKeyword); err != nil {
+			return nil, fmt.Errorf("[RateLimit] failed to get cache service: %w", err)
+		}
+	}
+
+	store, err := newStore(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		name:  name,
+		limit: limit,
+		opt:   opt,
+		store: store,
+	}, nil
+}
+
+// Name returns the name of the limiter.
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Limit returns the limit of the limiter.
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Allow takes one request for the key if the limit allows it.
+func (l *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+// AllowN takes n requests for the key if the limit allows all of them.
+func (l *Limiter) AllowN(ctx context.Context, key string, n int) (*Result, error) {
+	result, err := l.take(ctx, key, n, false)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Reserve takes one request for the key even if the limit is reached; the reservation
+// tells how long to wait before acting on it.
+func (l *Limiter) Reserve(ctx context.Context, key string) (*Reservation, error) {
+	return l.ReserveN(ctx, key, 1)
+}
+
+// ReserveN takes n requests for the key even if the limit is reached.
+func (l *Limiter) ReserveN(ctx context.Context, key string, n int) (*Reservation, error) {
+	result, err := l.take(ctx, key, n, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{Result: result, Delay: result.RetryAfter}, nil
+}
+
+// Wait blocks until one request for the key is allowed, or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	for {
+		result, err := l.Allow(ctx, key)
+		if err != nil || result.Allowed {
+			return err
+		}
+
+		reservation := Reservation{Delay: result.RetryAfter}
+		if err := reservation.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// Reset clears the counters of the key.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.store.reset(ctx, l.opt.prefix+key)
+}
+
+// take applies the algorithm to the counters of the key
+func (l *Limiter) take(ctx context.Context, key string, n int, reserve bool) (Result, error) {
+	if n <= 0 {
+		return Result{}, fmt.Errorf("[RateLimit] invalid number of requests %d", n)
+	}
+	if n > l.limit.capacity(l.opt.algorithm) {
+		return Result{}, ErrExceedsLimit
+	}
+
+	result, err := l.store.take(ctx, l.opt.algorithm, l.opt.prefix+key, l.limit, n, reserve)
+	if err != nil {
+		return Result{}, fmt.Errorf("[RateLimit] failed to take requests for %s: %w", key, err)
+	}
+
+	result.Limit = l.limit.capacity(l.opt.algorithm)
+	return result, nil
+}
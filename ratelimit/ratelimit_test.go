@@ -0,0 +1,397 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/cache"
+	"github.com/HemendCo/go-core/cache/cache_models"
+	corehttp "github.com/HemendCo/go-core/http"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// second is a period in the microseconds of the algorithms
+const second = int64(1_000_000)
+
+// epoch is a time at the start of a window of every period of the tests
+const epoch = 1_700_000_000 * second
+
+// operation takes n requests at an offset from the epoch
+type operation struct {
+	at      int64
+	n       int64
+	reserve bool
+}
+
+// run applies the operations to a state and returns the results
+func run(algorithm Algorithm, limit Limit, ops []operation) []step {
+	var state []int64
+	steps := make([]step, len(ops))
+	for i, op := range ops {
+		steps[i] = apply(algorithm, state, epoch+op.at, limit, op.n, op.reserve)
+		state = steps[i].state
+	}
+	return steps
+}
+
+// repeat returns count operations of one request at the offset
+func repeat(count int, at int64, reserve bool) []operation {
+	ops := make([]operation, count)
+	for i := range ops {
+		ops[i] = operation{at: at, n: 1, reserve: reserve}
+	}
+	return ops
+}
+
+func TestAlgorithms(t *testing.T) {
+	limit := PerSecond(5)
+
+	// want lists the allowed flag, remaining, retry after and reset after of the last operation
+	tests := []struct {
+		name      string
+		algorithm Algorithm
+		limit     Limit
+		ops       []operation
+		want      [4]int64
+	}{
+		{name: "token bucket first request", algorithm: TokenBucket, ops: repeat(1, 0, false), want: [4]int64{1, 4, 0, 200_000}},
+		{name: "token bucket exhausted", algorithm: TokenBucket, ops: repeat(5, 0, false), want: [4]int64{1, 0, 0, second}},
+		{name: "token bucket denied", algorithm: TokenBucket, ops: repeat(6, 0, false), want: [4]int64{0, 0, 200_000, second}},
+		{name: "token bucket refill", algorithm: TokenBucket, ops: append(repeat(5, 0, false), operation{at: 400_000, n: 1}), want: [4]int64{1, 1, 0, 800_000}},
+		{name: "token bucket reservations", algorithm: TokenBucket, ops: repeat(8, 0, true), want: [4]int64{0, 0, 600_000, 1_600_000}},
+		{name: "token bucket burst", algorithm: TokenBucket, limit: Limit{Rate: 5, Period: time.Second, Burst: 10}, ops: repeat(10, 0, false), want: [4]int64{1, 0, 0, 2 * second}},
+
+		{name: "fixed window first request", algorithm: FixedWindow, ops: repeat(1, 250_000, false), want: [4]int64{1, 4, 0, 750_000}},
+		{name: "fixed window denied", algorithm: FixedWindow, ops: repeat(6, 250_000, false), want: [4]int64{0, 0, 750_000, 750_000}},
+		{name: "fixed window next window", algorithm: FixedWindow, ops: append(repeat(5, 0, false), operation{at: second, n: 5}), want: [4]int64{1, 0, 0, second}},
+		{name: "fixed window reservations", algorithm: FixedWindow, ops: repeat(11, 0, true), want: [4]int64{0, 0, 2 * second, 3 * second}},
+		{name: "fixed window carried reservations", algorithm: FixedWindow, ops: append(repeat(11, 0, true), operation{at: second, n: 1}), want: [4]int64{0, 0, second, 2 * second}},
+
+		{name: "sliding window first request", algorithm: SlidingWindow, ops: repeat(1, 0, false), want: [4]int64{1, 4, 0, 2 * second}},
+		{name: "sliding window denied", algorithm: SlidingWindow, ops: repeat(6, 0, false), want: [4]int64{0, 0, 1_200_000, 2 * second}},
+		{name: "sliding window previous fades out", algorithm: SlidingWindow, ops: append(repeat(5, 0, false), operation{at: 1_600_000, n: 3}), want: [4]int64{1, 0, 0, 1_400_000}},
+		{name: "sliding window previous still weighs", algorithm: SlidingWindow, ops: append(repeat(5, 0, false), operation{at: 1_200_000, n: 3}), want: [4]int64{0, 0, 400_000, 800_000}},
+		{name: "sliding window whole limit", algorithm: SlidingWindow, ops: append(repeat(1, 0, false), operation{at: 0, n: 5}), want: [4]int64{0, 0, 2 * second, 2 * second}},
+		{name: "sliding window reservations", algorithm: SlidingWindow, ops: repeat(9, 0, true), want: [4]int64{0, 0, 1_800_000, 3 * second}},
+		// The tenth request would end the second window with its previous window still weighing, it moves to the third
+		{name: "sliding window reservation moves to the next window", algorithm: SlidingWindow, ops: repeat(10, 0, true), want: [4]int64{0, 0, 2 * second, 4 * second}},
+		{name: "sliding window reservations beyond two windows", algorithm: SlidingWindow, ops: repeat(15, 0, true), want: [4]int64{0, 0, 3_400_000, 5 * second}},
+		{name: "sliding window carried reservations", algorithm: SlidingWindow, ops: append(repeat(15, 0, true), operation{at: 2_100_000, n: 1}), want: [4]int64{0, 0, 1_500_000, 2_900_000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.limit.Rate == 0 {
+				tt.limit = limit
+			}
+
+			steps := run(tt.algorithm, tt.limit, tt.ops)
+			last := steps[len(steps)-1]
+
+			allowed := int64(0)
+			if last.allowed {
+				allowed = 1
+			}
+			if got := [4]int64{allowed, last.remaining, last.retryAfter, last.resetAfter}; got != tt.want {
+				t.Fatalf("allowed, remaining, retry after, reset after = %v, want %v", got, tt.want)
+			}
+			if last.ttl < last.resetAfter {
+				t.Fatalf("the state expires after %d, before the limit resets after %d", last.ttl, last.resetAfter)
+			}
+		})
+	}
+}
+
+// randomOperations returns operations of up to capacity requests at increasing offsets
+func randomOperations(seed int64, count int, capacity int64) []operation {
+	random := rand.New(rand.NewSource(seed))
+
+	ops := make([]operation, count)
+	at := int64(0)
+	for i := range ops {
+		at += random.Int63n(second / 4)
+		ops[i] = operation{at: at, n: 1 + random.Int63n(capacity), reserve: random.Intn(2) == 0}
+	}
+	return ops
+}
+
+// event is the time requests are acted upon
+type event struct {
+	at int64
+	n  int64
+}
+
+// events returns the times the allowed and reserved requests are acted upon, in order
+func events(ops []operation, steps []step) []event {
+	var res []event
+	for i, op := range ops {
+		if steps[i].allowed || op.reserve {
+			res = append(res, event{at: op.at + steps[i].retryAfter, n: op.n})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].at < res[j].at })
+	return res
+}
+
+func TestReservationsRespectTheLimit(t *testing.T) {
+	limit := PerSecond(10)
+	period := limit.Period.Microseconds()
+
+	checks := map[Algorithm]func(t *testing.T, acted []event){
+		TokenBucket: func(t *testing.T, acted []event) {
+			// Any interval holds the burst and the requests refilled during it
+			interval := float64(period) / float64(limit.Rate)
+			for i := range acted {
+				count := int64(0)
+				for j := i; j < len(acted); j++ {
+					count += acted[j].n
+					if allowed := float64(limit.Rate) + float64(acted[j].at-acted[i].at+1)/interval; float64(count) > allowed {
+						t.Fatalf("%d requests within [%d, %d], want at most %.2f", count, acted[i].at, acted[j].at, allowed)
+					}
+				}
+			}
+		},
+		FixedWindow: func(t *testing.T, acted []event) {
+			counts := make(map[int64]int64)
+			for _, e := range acted {
+				counts[e.at/period] += e.n
+			}
+			for window, count := range counts {
+				if count > int64(limit.Rate) {
+					t.Fatalf("%d requests in window %d, want at most %d", count, window, limit.Rate)
+				}
+			}
+		},
+		SlidingWindow: func(t *testing.T, acted []event) {
+			// The estimate of the requests acted upon never exceeds the rate when they are acted upon
+			for _, e := range acted {
+				window := e.at / period
+				previous, current := int64(0), int64(0)
+				for _, other := range acted {
+					switch {
+					case other.at/period == window-1:
+						previous += other.n
+					case other.at/period == window && other.at <= e.at:
+						current += other.n
+					}
+				}
+				weight := float64(period-e.at%period) / float64(period)
+				if estimate := float64(previous)*weight + float64(current); estimate > float64(limit.Rate)+1e-9 {
+					t.Fatalf("estimate %.2f at %d, want at most %d", estimate, e.at, limit.Rate)
+				}
+			}
+		},
+	}
+
+	for algorithm, check := range checks {
+		t.Run(string(algorithm), func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				ops := randomOperations(seed, 200, int64(limit.capacity(algorithm)))
+				steps := run(algorithm, limit, ops)
+
+				// Reservations of a key are acted upon in the order they are taken
+				last := int64(0)
+				for i, op := range ops {
+					if !op.reserve {
+						continue
+					}
+					if at := op.at + steps[i].retryAfter; at < last {
+						t.Fatalf("seed %d: reservation %d acts at %d, before the previous one at %d", seed, i, at, last)
+					} else {
+						last = at
+					}
+				}
+
+				check(t, events(ops, steps))
+			}
+		})
+	}
+}
+
+// redisDriver exposes the client of the test server to the redis store
+type redisDriver struct {
+	client *redis.Client
+}
+
+func (d *redisDriver) Client() *redis.Client {
+	return d.client
+}
+
+func TestRedisScriptsMirrorAlgorithms(t *testing.T) {
+	server := miniredis.RunT(t)
+	driver := &redisDriver{client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+	defer driver.client.Close()
+	store := &redisStore{driver: driver}
+
+	limits := []Limit{PerSecond(10), {Rate: 3, Period: 700 * time.Millisecond, Burst: 6}}
+	ctx := context.Background()
+
+	for _, algorithm := range []Algorithm{TokenBucket, FixedWindow, SlidingWindow} {
+		for i, limit := range limits {
+			key := string(algorithm) + "_" + string(rune('a'+i))
+			ops := randomOperations(int64(i+1), 100, int64(limit.capacity(algorithm)))
+			steps := run(algorithm, limit, ops)
+
+			for j, op := range ops {
+				server.SetTime(time.UnixMicro(epoch + op.at))
+
+				got, err := store.take(ctx, algorithm, key, limit, int(op.n), op.reserve)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := steps[j].result(); got != want {
+					t.Fatalf("%s %+v operation %d %+v: redis %+v, want %+v", algorithm, limit, j, op, got, want)
+				}
+			}
+		}
+	}
+}
+
+// newStores returns the cache drivers the limiters are tested with
+func newStores(t *testing.T) map[string]cache.CacheDriver {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	manager := cache.NewCacheManager()
+
+	mapDriver, err := manager.CreateCacheFactory("map", cache_models.MapCacheConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	redisDriver, err := manager.CreateCacheFactory("redis", cache_models.RedisCacheConfig{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]cache.CacheDriver{"map": mapDriver, "redis": redisDriver}
+}
+
+func TestLimiterRepeatedReserve(t *testing.T) {
+	ctx := context.Background()
+
+	for name, driver := range newStores(t) {
+		for _, algorithm := range []Algorithm{TokenBucket, FixedWindow, SlidingWindow} {
+			t.Run(name+"/"+string(algorithm), func(t *testing.T) {
+				limiter, err := NewLimiter(core.NewApp(), "reserve", PerHour(5), Using(algorithm), Store(driver))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				start := time.Now()
+				var previous time.Duration // Time from start the previous reservation acts at
+				for i := 1; i <= 15; i++ {
+					reservation, err := limiter.Reserve(ctx, "key")
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					// Delays are rounded to microseconds, and to milliseconds by redis
+					at := time.Since(start) + reservation.Delay
+					if reservation.Allowed != (i <= 5) || at < previous-time.Millisecond {
+						t.Fatalf("reservation %d: allowed %v, acting after %v; the previous one acts after %v", i, reservation.Allowed, at, previous)
+					}
+					previous = at
+				}
+
+				// Fifteen requests at five per hour take two more hours, or two more windows that
+				// start within the hour
+				if wait := map[Algorithm]time.Duration{TokenBucket: 2 * time.Hour, FixedWindow: time.Hour, SlidingWindow: 2 * time.Hour}[algorithm]; previous < wait-time.Minute {
+					t.Fatalf("the last reservation acts after %v, want at least %v", previous, wait)
+				}
+
+				if err := limiter.Reset(ctx, "key"); err != nil {
+					t.Fatal(err)
+				}
+				if result, err := limiter.Allow(ctx, "key"); err != nil || !result.Allowed {
+					t.Fatalf("Allow after Reset = %+v, %v", result, err)
+				}
+			})
+		}
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	driver := newStores(t)["map"]
+	app := core.NewApp()
+
+	tests := []struct {
+		name  string
+		limit Limit
+		opts  []Option
+	}{
+		{name: "zero rate", limit: Limit{Period: time.Second}},
+		{name: "zero period", limit: Limit{Rate: 1}},
+		{name: "negative burst", limit: Limit{Rate: 1, Period: time.Second, Burst: -1}},
+		{name: "unsupported algorithm", limit: PerSecond(1), opts: []Option{Using("leaky_bucket")}},
+		{name: "no cache service", limit: PerSecond(1)},
+	}
+	for _, tt := range tests {
+		if _, err := NewLimiter(app, tt.name, tt.limit, append(tt.opts, Store(driver))...); err == nil && tt.name != "no cache service" {
+			t.Errorf("%s: NewLimiter succeeded", tt.name)
+		}
+		if tt.name == "no cache service" {
+			if _, err := NewLimiter(app, tt.name, tt.limit); err == nil {
+				t.Errorf("%s: NewLimiter succeeded", tt.name)
+			}
+		}
+	}
+
+	limiter, err := NewLimiter(app, "exceeds", Limit{Rate: 2, Period: time.Second, Burst: 4}, Store(driver))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.AllowN(context.Background(), "key", 5); !errors.Is(err, ErrExceedsLimit) {
+		t.Fatalf("AllowN beyond the burst = %v, want ErrExceedsLimit", err)
+	}
+	if _, err := limiter.AllowN(context.Background(), "key", 0); err == nil {
+		t.Fatal("AllowN of no request succeeded")
+	}
+	if result, err := limiter.AllowN(context.Background(), "key", 4); err != nil || !result.Allowed || result.Limit != 4 {
+		t.Fatalf("AllowN of the burst = %+v, %v", result, err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	limiter, err := NewLimiter(core.NewApp(), "http", PerMinute(2), Using(FixedWindow), Store(newStores(t)["map"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := corehttp.NewRouter(core.NewApp())
+	router.Use(Middleware(limiter, ByIP))
+	router.GET("/", func(c *corehttp.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		remoteAddr    string
+		wantCode      int
+		wantRemaining string
+	}{
+		{remoteAddr: "10.0.0.1:1000", wantCode: http.StatusNoContent, wantRemaining: "1"},
+		{remoteAddr: "10.0.0.1:1001", wantCode: http.StatusNoContent, wantRemaining: "0"},
+		{remoteAddr: "10.0.0.1:1002", wantCode: http.StatusTooManyRequests, wantRemaining: "0"},
+		{remoteAddr: "10.0.0.2:1000", wantCode: http.StatusNoContent, wantRemaining: "1"},
+	}
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantCode || rec.Header().Get("X-RateLimit-Remaining") != tt.wantRemaining || rec.Header().Get("X-RateLimit-Limit") != "2" {
+			t.Fatalf("request %d: status %d, headers %v", i, rec.Code, rec.Header())
+		}
+		if denied := rec.Code == http.StatusTooManyRequests; denied != (rec.Header().Get("Retry-After") != "") {
+			t.Fatalf("request %d: Retry-After %q with status %d", i, rec.Header().Get("Retry-After"), rec.Code)
+		}
+	}
+}
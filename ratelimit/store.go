@@ -0,0 +1,273 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HemendCo/go-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// store keeps the counters of the limiters
+type store interface {
+	take(ctx context.Context, algorithm Algorithm, key string, limit Limit, n int, reserve bool) (Result, error)
+	reset(ctx context.Context, key string) error
+}
+
+// redisClient is implemented by the redis cache driver
+type redisClient interface {
+	Client() *redis.Client
+}
+
+// newStore returns the store of the cache driver
+func newStore(driver cache.CacheDriver) (store, error) {
+	switch driver := driver.(type) {
+	case redisClient:
+		return &redisStore{driver: driver}, nil
+	case cache.AtomicDriver:
+		return &cacheStore{driver: driver, cache: driver.(cache.CacheDriver)}, nil
+	default:
+		return nil, fmt.Errorf("[RateLimit] cache driver %s does not support atomic updates", driver.Name())
+	}
+}
+
+// cacheStore keeps the counters in a cache driver updated with Modify
+type cacheStore struct {
+	driver cache.AtomicDriver
+	cache  cache.CacheDriver
+}
+
+func (s *cacheStore) take(ctx context.Context, algorithm Algorithm, key string, limit Limit, n int, reserve bool) (Result, error) {
+	var res step
+	err := s.driver.Modify(key, func(value interface{}) (interface{}, time.Duration, error) {
+		state, err := decodeState(value)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		res = apply(algorithm, state, time.Now().UnixMicro(), limit, int64(n), reserve)
+		return encodeState(res.state), time.Duration(res.ttl) * time.Microsecond, nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return res.result(), nil
+}
+
+func (s *cacheStore) reset(ctx context.Context, key string) error {
+	return s.cache.Delete(key)
+}
+
+// encodeState formats the state as colon separated integers
+func encodeState(state []int64) string {
+	parts := make([]string, len(state))
+	for i, value := range state {
+		parts[i] = strconv.FormatInt(value, 10)
+	}
+	return strings.Join(parts, ":")
+}
+
+// decodeState parses a state formatted by encodeState
+func decodeState(value interface{}) ([]int64, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var encoded string
+	switch value := value.(type) {
+	case string:
+		encoded = value
+	case []byte:
+		encoded = string(value)
+	default:
+		return nil, fmt.Errorf("invalid rate limit state %T", value)
+	}
+
+	var state []int64
+	for _, part := range strings.Split(encoded, ":") {
+		number, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit state %q: %w", encoded, err)
+		}
+		state = append(state, number)
+	}
+	return state, nil
+}
+
+// redisStore keeps the counters in Redis, updated atomically by scripts
+type redisStore struct {
+	driver redisClient
+}
+
+func (s *redisStore) take(ctx context.Context, algorithm Algorithm, key string, limit Limit, n int, reserve bool) (Result, error) {
+	reserveArg := 0
+	if reserve {
+		reserveArg = 1
+	}
+
+	values, err := scripts[algorithm].Run(ctx, s.driver.Client(), []string{key},
+		limit.Period.Microseconds(), limit.Rate, limit.capacity(algorithm), n, reserveArg).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	return step{
+		allowed:    values[0] == 1,
+		remaining:  values[1],
+		retryAfter: values[2],
+		resetAfter: values[3],
+	}.result(), nil
+}
+
+func (s *redisStore) reset(ctx context.Context, key string) error {
+	return s.driver.Client().Del(ctx, key).Err()
+}
+
+// scriptHeader reads the arguments and the state shared by the scripts; they mirror the Go algorithms
+const scriptHeader = `
+local period = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local capacity = tonumber(ARGV[3])
+local n = tonumber(ARGV[4])
+local reserve = ARGV[5] == "1"
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local state = {}
+local value = redis.call("GET", KEYS[1])
+if value then
+	for part in string.gmatch(value, "[^:]+") do
+		state[#state + 1] = tonumber(part)
+	end
+end
+
+local function save(values, ttl)
+	local parts = {}
+	for i, v in ipairs(values) do
+		parts[i] = string.format("%.0f", v)
+	end
+	redis.call("SET", KEYS[1], table.concat(parts, ":"), "PX", math.max(1, math.ceil(ttl / 1000)))
+end
+`
+
+var scripts = map[Algorithm]*redis.Script{
+	TokenBucket: redis.NewScript(scriptHeader + `
+local interval = period / rate
+local tat = now
+if #state == 1 and state[1] > tat then
+	tat = state[1]
+end
+
+local newTat = tat + n * interval
+local allowAt = newTat - capacity * interval
+
+if now >= allowAt then
+	save({math.floor(newTat)}, math.ceil(newTat) - now)
+	return {1, math.floor((now - allowAt) / interval), 0, math.ceil(newTat) - now}
+end
+
+local retryAfter = math.ceil(allowAt) - now
+if reserve then
+	save({math.floor(newTat)}, math.ceil(newTat) - now)
+	return {0, 0, retryAfter, math.ceil(newTat) - now}
+end
+
+local remaining = math.max(0, math.floor((now - (tat - capacity * interval)) / interval))
+return {0, remaining, retryAfter, math.ceil(tat) - now}
+`),
+	FixedWindow: redis.NewScript(scriptHeader + `
+local start = now - now % period
+local count = 0
+if #state == 2 then
+	start, count = state[1], state[2]
+	local elapsed = math.floor((now - start) / period)
+	if elapsed > 0 then
+		start = start + elapsed * period
+		count = math.max(0, count - elapsed * rate)
+	end
+end
+
+local allowed = count + n <= rate
+local retryAfter = 0
+if allowed then
+	count = count + n
+else
+	local window = math.floor((count - 1) / rate)
+	if count + n > (window + 1) * rate then
+		window = window + 1
+	end
+	retryAfter = start + window * period - now
+
+	if reserve then
+		count = math.max(count, window * rate) + n
+	end
+end
+
+local windows = math.max(1, math.floor((count + rate - 1) / rate))
+local ttl = start + windows * period - now
+save({start, count}, ttl)
+return {allowed and 1 or 0, math.max(0, rate - count), retryAfter, ttl}
+`),
+	SlidingWindow: redis.NewScript(scriptHeader + `
+local start, previous, current = now - now % period, 0, 0
+if #state == 3 then
+	start, previous, current = state[1], state[2], state[3]
+	local elapsed = math.floor((now - start) / period)
+	if elapsed > 0 then
+		start = start + elapsed * period
+		previous = math.min(rate, math.max(0, current - (elapsed - 1) * rate))
+		current = math.max(0, current - elapsed * rate)
+	end
+end
+
+local weight = (period - (now - start)) / period
+local estimate = previous * weight + current
+
+local allowed = estimate + n <= rate
+local remaining, retryAfter = 0, 0
+if allowed then
+	current = current + n
+	remaining = math.floor(rate - estimate - n)
+else
+	local last = 0
+	if current > 0 then
+		last = math.floor((current - 1) / rate)
+	end
+	local window, count, before = last, current - last * rate + n, previous
+	if last > 0 then
+		before = rate
+	end
+	while count > rate or (count == rate and before > 0) do
+		before = count - n
+		count = n
+		window = window + 1
+	end
+
+	local at = start + window * period
+	local fade = count + before - rate
+	if fade > 0 then
+		at = at + math.ceil(fade * period / before)
+	end
+	retryAfter = math.max(0, at - now)
+
+	if reserve then
+		current = window * rate + count
+	end
+end
+
+local windows = 1
+if current > 0 then
+	windows = math.floor((current - 1) / rate) + 2
+end
+save({start, previous, current}, start + math.max(2, windows) * period - now)
+return {allowed and 1 or 0, remaining, retryAfter, start + windows * period - now}
+`),
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resolver retrieves registered services by key.
+type Resolver interface {
+	Get(key Keywords) (interface{}, error)
+}
+
+// Provide registers a typed creator function for the key.
+func Provide[T any](app *App, key Keywords, createFunc func() (T, error), opts ...ServiceOption) error {
+	return app.Use(key, func() (interface{}, error) {
+		instance, err := createFunc()
+		if err != nil {
+			return nil, err
+		}
+		return instance, nil
+	}, opts...)
+}
+
+// Resolve retrieves the service registered for the key as a T.
+// An error is returned if the service cannot be created or is not a T.
+func Resolve[T any](r Resolver, key Keywords) (T, error) {
+	var zero T
+
+	service, err := r.Get(key)
+	if err != nil {
+		return zero, err
+	}
+
+	instance, ok := service.(T)
+	if !ok {
+		return zero, fmt.Errorf("[App] service %s has type %T, expected %s", key, service, reflect.TypeFor[T]())
+	}
+
+	return instance, nil
+}
+
+// MustResolve is like Resolve but panics if the service cannot be resolved.
+func MustResolve[T any](r Resolver, key Keywords) T {
+	instance, err := Resolve[T](r, key)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// greeter is the interface of a typed test service
+type greeter interface {
+	Greet() string
+}
+
+type english struct{}
+
+func (english) Greet() string { return "hello" }
+
+func TestResolve(t *testing.T) {
+	app := NewApp(Retry(RetryPolicy{}))
+	if err := Provide(app, "greeter", func() (*english, error) { return &english{}, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := Provide(app, "broken", func() (*english, error) { return nil, errors.New("unavailable") }); err != nil {
+		t.Fatal(err)
+	}
+	if err := Provide(app, "invalid key", func() (int, error) { return 0, nil }); err == nil {
+		t.Fatal("Provide accepted an invalid key")
+	}
+
+	tests := []struct {
+		name    string
+		resolve func() (interface{}, error)
+		wantErr string
+	}{
+		{name: "concrete type", resolve: func() (interface{}, error) { return Resolve[*english](app, "greeter") }},
+		{name: "interface", resolve: func() (interface{}, error) { return Resolve[greeter](app, "greeter") }},
+		{name: "type mismatch", resolve: func() (interface{}, error) { return Resolve[fmt.Stringer](app, "greeter") }, wantErr: "service greeter has type *core.english, expected fmt.Stringer"},
+		{name: "value instead of pointer", resolve: func() (interface{}, error) { return Resolve[english](app, "greeter") }, wantErr: "expected core.english"},
+		{name: "missing service", resolve: func() (interface{}, error) { return Resolve[*english](app, "missing") }, wantErr: "no instance found for key missing"},
+		{name: "construction failure", resolve: func() (interface{}, error) { return Resolve[*english](app, "broken") }, wantErr: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, err := tt.resolve()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if instance.(greeter).Greet() != "hello" {
+					t.Fatalf("resolved %#v", instance)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMustResolve(t *testing.T) {
+	app := NewApp()
+	if err := Provide(app, "greeter", func() (greeter, error) { return english{}, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustResolve[greeter](app, "greeter").Greet(); got != "hello" {
+		t.Fatalf("Greet() = %q, want hello", got)
+	}
+
+	tests := []struct {
+		name    string
+		resolve func()
+		wantErr string
+	}{
+		{name: "type mismatch", resolve: func() { MustResolve[*english](app, "greeter") }, wantErr: "service greeter has type core.english, expected *core.english"},
+		{name: "missing service", resolve: func() { MustResolve[greeter](app, "missing") }, wantErr: "no instance found for key missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, ok := recover().(error)
+				if !ok || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("panic %v, want an error containing %q", err, tt.wantErr)
+				}
+			}()
+			tt.resolve()
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/HemendCo/go-core/locks"
+)
+
+const (
+	// DefaultLeaderKey is the lock key replicas compete for to run the entries
+	DefaultLeaderKey = "scheduler_leader"
+	// DefaultLeaderTTL is how long a leader keeps the lead without renewing it
+	DefaultLeaderTTL = 30 * time.Second
+	// DefaultOverlapTTL bounds how long an entry without overlapping is considered running
+	DefaultOverlapTTL = time.Hour
+)
+
+type Option interface {
+}
+
+// option holds the settings of a Scheduler
+type option struct {
+	location  *time.Location
+	locker    *locks.Locker
+	leaderKey string
+	leaderTTL time.Duration
+}
+
+// Internal option representations.
+type (
+	locationOption  struct{ location *time.Location }
+	lockerOption    struct{ locker *locks.Locker }
+	leaderKeyOption string
+	leaderTTLOption time.Duration
+)
+
+// Location returns an option to specify the location of the cron expressions, UTC by default.
+func Location(location *time.Location) Option {
+	return locationOption{location: location}
+}
+
+// Locker returns an option to elect the leader with the given locker instead of the locks service.
+func Locker(locker *locks.Locker) Option {
+	return lockerOption{locker: locker}
+}
+
+// LeaderKey returns an option to specify the lock key replicas compete for.
+func LeaderKey(key string) Option {
+	return leaderKeyOption(key)
+}
+
+// LeaderTTL returns an option to specify how long a leader keeps the lead without renewing it.
+func LeaderTTL(ttl time.Duration) Option {
+	return leaderTTLOption(ttl)
+}
+
+func composeOptions(opts ...Option) option {
+	res := option{
+		location:  time.UTC,
+		leaderKey: DefaultLeaderKey,
+		leaderTTL: DefaultLeaderTTL,
+	}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case locationOption:
+			if opt.location != nil {
+				res.location = opt.location
+			}
+		case lockerOption:
+			res.locker = opt.locker
+		case leaderKeyOption:
+			if opt != "" {
+				res.leaderKey = string(opt)
+			}
+		case leaderTTLOption:
+			if opt > 0 {
+				res.leaderTTL = time.Duration(opt)
+			}
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
+
+type EntryOption interface {
+}
+
+// entryOption holds the settings of an Entry
+type entryOption struct {
+	name        string
+	timezone    string
+	queued      bool
+	overlapping bool
+	overlapTTL  time.Duration
+}
+
+// Internal entry option representations.
+type (
+	nameOption               string
+	timezoneOption           string
+	queuedOption             struct{}
+	withoutOverlappingOption time.Duration
+)
+
+// Name returns an entry option to specify the name of the entry, the job type name by default.
+func Name(name string) EntryOption {
+	return nameOption(name)
+}
+
+// Timezone returns an entry option to evaluate the cron expression in the named location,
+// e.g. "Asia/Tehran", instead of the location of the scheduler.
+func Timezone(name string) EntryOption {
+	return timezoneOption(name)
+}
+
+// Queued returns an entry option to enqueue the job with the worker service instead of running it inline.
+func Queued() EntryOption {
+	return queuedOption{}
+}
+
+// WithoutOverlapping returns an entry option to skip a run while the previous one is still running.
+// With a locker, runs are not overlapping across replicas either; ttl bounds how long a run
+// that did not finish, e.g. because its replica died, is considered running.
+func WithoutOverlapping(ttl time.Duration) EntryOption {
+	return withoutOverlappingOption(ttl)
+}
+
+func composeEntryOptions(opts ...EntryOption) entryOption {
+	res := entryOption{
+		overlapping: true,
+	}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case nameOption:
+			res.name = string(opt)
+		case timezoneOption:
+			res.timezone = string(opt)
+		case queuedOption:
+			res.queued = true
+		case withoutOverlappingOption:
+			res.overlapping = false
+			res.overlapTTL = time.Duration(opt)
+			if res.overlapTTL <= 0 {
+				res.overlapTTL = DefaultOverlapTTL
+			}
+		default:
+			// ignore unexpected option
+		}
+	}
+	return res
+}
@@ -0,0 +1,343 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/locks"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+	"github.com/robfig/cron/v3"
+)
+
+// parser accepts cron expressions with an optional seconds field and descriptors such as
+// "@hourly" or "@every 5m"
+var parser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Entry is a job scheduled by the scheduler.
+type Entry struct {
+	scheduler *Scheduler
+	id        cron.EntryID
+	job       worker_interfaces.Job
+	params    interface{}
+	opt       entryOption
+	running   atomic.Bool
+}
+
+// Name returns the name of the entry.
+func (e *Entry) Name() string {
+	return e.opt.name
+}
+
+// Next returns the next time the entry runs, or the zero time if the scheduler is not running.
+func (e *Entry) Next() time.Time {
+	return e.scheduler.cron.Entry(e.id).Next
+}
+
+// Prev returns the last time the entry ran, or the zero time if it did not run yet.
+func (e *Entry) Prev() time.Time {
+	return e.scheduler.cron.Entry(e.id).Prev
+}
+
+// Scheduler runs worker jobs on cron expressions or intervals. When a locker is available,
+// only the replica holding the leader lock runs the entries.
+type Scheduler struct {
+	app     *core.App
+	opt     option
+	cron    *cron.Cron
+	mu      sync.Mutex
+	entries map[string]*Entry
+
+	lock        *locks.Lock  // Leader lock held by this replica
+	leaderUntil atomic.Int64 // Unix nanoseconds until which this replica leads
+	cancel      context.CancelFunc
+	done        chan struct{} // Closed when the leader election has stopped
+}
+
+// NewScheduler creates a scheduler. The leader is elected with the locks service of the app,
+// if registered, otherwise the scheduler always runs its entries.
+func NewScheduler(app *core.App, opts ...Option) (*Scheduler, error) {
+	opt := composeOptions(opts...)
+
+	if opt.locker == nil && app.Exists(core.LocksKeyword) {
+		locker, err := core.Resolve[*locks.Locker](app, core.LocksKeyword)
+		if err != nil {
+			return nil, fmt.Errorf("[Scheduler] failed to get locks service: %w", err)
+		}
+		opt.locker = locker
+	}
+
+	return &Scheduler{
+		app:     app,
+		opt:     opt,
+		cron:    cron.New(cron.WithLocation(opt.location), cron.WithParser(parser)),
+		entries: make(map[string]*Entry),
+	}, nil
+}
+
+// Cron schedules the job with params on a cron expression, e.g. "0 3 * * *", "*/10 * * * * *"
+// with seconds, or a descriptor such as "@daily".
+func (s *Scheduler) Cron(spec string, job worker_interfaces.Job, params interface{}, opts ...EntryOption) (*Entry, error) {
+	opt := composeEntryOptions(opts...)
+	if opt.timezone != "" {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", opt.timezone, spec)
+	}
+
+	schedule, err := parser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("[Scheduler] invalid cron expression %q: %w", spec, err)
+	}
+
+	return s.schedule(schedule, job, params, opt)
+}
+
+// Every schedules the job with params to run at every interval, rounded to the second.
+func (s *Scheduler) Every(interval time.Duration, job worker_interfaces.Job, params interface{}, opts ...EntryOption) (*Entry, error) {
+	if interval < time.Second {
+		return nil, fmt.Errorf("[Scheduler] invalid interval %s: expected at least one second", interval)
+	}
+
+	return s.schedule(cron.Every(interval), job, params, composeEntryOptions(opts...))
+}
+
+// schedule adds an entry running the job on the schedule
+func (s *Scheduler) schedule(schedule cron.Schedule, job worker_interfaces.Job, params interface{}, opt entryOption) (*Entry, error) {
+	if opt.name == "" {
+		opt.name = job.TypeName()
+	}
+
+	if opt.queued && !opt.overlapping {
+		return nil, fmt.Errorf("[Scheduler] entry %s: queued entries cannot prevent overlapping, the worker runs them", opt.name)
+	}
+
+	if opt.queued && !s.app.Exists(core.WorkerKeyword) {
+		return nil, fmt.Errorf("[Scheduler] entry %s: queued entries need the worker service", opt.name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[opt.name]; exists {
+		return nil, fmt.Errorf("[Scheduler] entry %s is already scheduled", opt.name)
+	}
+
+	entry := &Entry{scheduler: s, job: job, params: params, opt: opt}
+	entry.id = s.cron.Schedule(schedule, cron.FuncJob(func() { s.run(entry) }))
+	s.entries[opt.name] = entry
+
+	return entry, nil
+}
+
+// Remove unschedules the named entry.
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, exists := s.entries[name]; exists {
+		s.cron.Remove(entry.id)
+		delete(s.entries, name)
+	}
+}
+
+// Entry returns the named entry, or nil if it is not scheduled.
+func (s *Scheduler) Entry(name string) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.entries[name]
+}
+
+// Entries returns the scheduled entries.
+func (s *Scheduler) Entries() []*Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]*Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// IsLeader reports whether this replica runs the entries.
+func (s *Scheduler) IsLeader() bool {
+	if s.opt.locker == nil {
+		return true
+	}
+	return time.Now().UnixNano() < s.leaderUntil.Load()
+}
+
+// Start starts the leader election and runs the entries until Shutdown.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return errors.New("[Scheduler] scheduler is already running")
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	if s.opt.locker != nil {
+		s.campaign(ctx)
+		go s.elect(ctx)
+	} else {
+		close(s.done)
+	}
+
+	s.cron.Start()
+	return nil
+}
+
+// Shutdown stops running the entries, waits for the running ones or ctx and resigns the lead.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	var err error
+	select {
+	case <-s.cron.Stop().Done():
+	case <-ctx.Done():
+		err = fmt.Errorf("[Scheduler] running entries did not finish: %w", ctx.Err())
+	}
+
+	cancel()
+	<-done
+
+	if s.lock != nil {
+		s.leaderUntil.Store(0)
+		if releaseErr := s.lock.Release(ctx); releaseErr != nil && !errors.Is(releaseErr, locks.ErrNotOwner) {
+			err = errors.Join(err, releaseErr)
+		}
+		s.lock = nil
+	}
+
+	return err
+}
+
+// elect renews or takes the lead until ctx is done
+func (s *Scheduler) elect(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opt.leaderTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.campaign(ctx)
+		}
+	}
+}
+
+// campaign renews the lead of this replica, or takes it if no replica leads
+func (s *Scheduler) campaign(ctx context.Context) {
+	ttl := s.opt.leaderTTL
+	until := time.Now().Add(ttl).UnixNano()
+
+	if s.lock != nil {
+		err := s.lock.Extend(ctx, ttl)
+		if err == nil {
+			s.leaderUntil.Store(until)
+			return
+		}
+		if !errors.Is(err, locks.ErrNotOwner) {
+			// The lead lasts until its ttl passes unless it can be renewed
+			log.Printf("[Scheduler] failed to renew the lead: %v", err)
+			return
+		}
+
+		log.Printf("[Scheduler] lost the lead")
+		s.lock = nil
+		s.leaderUntil.Store(0)
+	}
+
+	lock, err := s.opt.locker.TryAcquire(ctx, s.opt.leaderKey, ttl)
+	if err != nil {
+		if !errors.Is(err, locks.ErrNotAcquired) {
+			log.Printf("[Scheduler] failed to take the lead: %v", err)
+		}
+		return
+	}
+
+	s.lock = lock
+	s.leaderUntil.Store(until)
+}
+
+// run runs or enqueues the job of the entry if this replica leads
+func (s *Scheduler) run(entry *Entry) {
+	if !s.IsLeader() {
+		return
+	}
+
+	if !entry.opt.overlapping {
+		if !entry.running.CompareAndSwap(false, true) {
+			log.Printf("[Scheduler] entry %s is still running, skipping", entry.Name())
+			return
+		}
+		defer entry.running.Store(false)
+
+		if s.opt.locker != nil {
+			ctx := s.app.GetContext()
+			lock, err := s.opt.locker.TryAcquire(ctx, s.opt.leaderKey+"_"+entry.Name(), entry.opt.overlapTTL)
+			if err != nil {
+				if errors.Is(err, locks.ErrNotAcquired) {
+					log.Printf("[Scheduler] entry %s is still running on another replica, skipping", entry.Name())
+				} else {
+					log.Printf("[Scheduler] entry %s failed: %v", entry.Name(), err)
+				}
+				return
+			}
+			defer lock.Release(context.WithoutCancel(ctx))
+		}
+	}
+
+	if err := s.execute(entry); err != nil {
+		log.Printf("[Scheduler] entry %s failed: %v", entry.Name(), err)
+	}
+}
+
+// execute enqueues the job of a queued entry, or runs its handler with the task payload
+func (s *Scheduler) execute(entry *Entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if entry.opt.queued {
+		worker, err := core.Resolve[worker_interfaces.WorkerDriver](s.app, core.WorkerKeyword)
+		if err != nil {
+			return err
+		}
+		return worker.Enqueue(entry.job, entry.params)
+	}
+
+	task, err := entry.job.NewTask(s.app, entry.params)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	return entry.job.Handler(s.app, payload)
+}
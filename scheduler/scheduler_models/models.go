@@ -0,0 +1,9 @@
+package scheduler_models
+
+import "time"
+
+type SchedulerConfig struct {
+	Timezone  string        `mapstructure:"timezone"`   // Location of the cron expressions, UTC by default
+	LeaderKey string        `mapstructure:"leader_key"` // Lock key of the leader, "scheduler_leader" by default
+	LeaderTTL time.Duration `mapstructure:"leader_ttl"` // Time a leader keeps the lead without renewing it, 30s by default
+}
@@ -0,0 +1,390 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/locks"
+	"github.com/HemendCo/go-core/locks/locks_models"
+	"github.com/HemendCo/go-core/worker/worker_interfaces"
+)
+
+// recordJob keeps the params of its runs; runs wait for release when it is set
+type recordJob struct {
+	mu      sync.Mutex
+	params  []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (j *recordJob) TypeName() string {
+	return "record"
+}
+
+func (j *recordJob) NewTask(app *core.App, params interface{}) (interface{}, error) {
+	return params, nil
+}
+
+func (j *recordJob) Handler(app *core.App, payload []byte) error {
+	var params string
+	if err := json.Unmarshal(payload, &params); err != nil {
+		return err
+	}
+	if params == "panic" {
+		panic("job failed")
+	}
+
+	j.mu.Lock()
+	j.params = append(j.params, params)
+	j.mu.Unlock()
+
+	if j.started != nil {
+		j.started <- struct{}{}
+	}
+	if j.release != nil {
+		<-j.release
+	}
+	return nil
+}
+
+func (j *recordJob) runs() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]string(nil), j.params...)
+}
+
+// queueWorker is a worker keeping the params of the enqueued jobs
+type queueWorker struct {
+	mu     sync.Mutex
+	params []interface{}
+}
+
+func (w *queueWorker) Name() string                                          { return "queue" }
+func (w *queueWorker) Init(app *core.App, config interface{}) error          { return nil }
+func (w *queueWorker) RegisterJobHandlers(handlers ...worker_interfaces.Job) {}
+func (w *queueWorker) JobHandlerExists(handler worker_interfaces.Job) bool   { return true }
+func (w *queueWorker) Close() error                                          { return nil }
+func (w *queueWorker) Run(handlers ...worker_interfaces.Job) error           { return nil }
+
+func (w *queueWorker) Enqueue(job worker_interfaces.Job, params interface{}) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.params = append(w.params, params)
+	return nil
+}
+
+// newLocker creates a locker over a map lock driver
+func newLocker(t *testing.T) *locks.Locker {
+	t.Helper()
+
+	driver, err := locks.NewLocksManager().CreateLocksFactory("map", locks_models.MapLockConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return locks.NewLocker(driver)
+}
+
+// newScheduler creates a scheduler and shuts it down at the end of the test
+func newScheduler(t *testing.T, app *core.App, opts ...Option) *Scheduler {
+	t.Helper()
+
+	s, err := NewScheduler(app, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s
+}
+
+func TestSchedule(t *testing.T) {
+	s := newScheduler(t, core.NewApp())
+	job := &recordJob{}
+	if _, err := s.Cron("@daily", job, nil, Name("taken")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		schedule func() (*Entry, error)
+		wantName string
+		wantErr  bool
+	}{
+		{name: "cron", schedule: func() (*Entry, error) { return s.Cron("0 3 * * *", job, nil) }, wantName: "record"},
+		{name: "cron with seconds", schedule: func() (*Entry, error) { return s.Cron("*/10 * * * * *", job, nil, Name("seconds")) }, wantName: "seconds"},
+		{name: "descriptor", schedule: func() (*Entry, error) { return s.Cron("@every 5m", job, nil, Name("every")) }, wantName: "every"},
+		{name: "interval", schedule: func() (*Entry, error) { return s.Every(time.Minute, job, nil, Name("interval")) }, wantName: "interval"},
+		{name: "invalid cron", schedule: func() (*Entry, error) { return s.Cron("every day", job, nil, Name("invalid")) }, wantErr: true},
+		{name: "invalid timezone", schedule: func() (*Entry, error) { return s.Cron("@daily", job, nil, Name("tz"), Timezone("Nowhere/City")) }, wantErr: true},
+		{name: "interval under a second", schedule: func() (*Entry, error) { return s.Every(time.Millisecond, job, nil, Name("fast")) }, wantErr: true},
+		{name: "name already scheduled", schedule: func() (*Entry, error) { return s.Cron("@hourly", job, nil, Name("taken")) }, wantErr: true},
+		{name: "queued without the worker", schedule: func() (*Entry, error) { return s.Cron("@daily", job, nil, Name("queued"), Queued()) }, wantErr: true},
+		{name: "queued without overlapping", schedule: func() (*Entry, error) {
+			return s.Cron("@daily", job, nil, Name("queued"), Queued(), WithoutOverlapping(time.Minute))
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := tt.schedule()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if entry.Name() != tt.wantName || s.Entry(tt.wantName) != entry {
+				t.Fatalf("entry %q scheduled, want %q", entry.Name(), tt.wantName)
+			}
+		})
+	}
+
+	if got := len(s.Entries()); got != 5 {
+		t.Fatalf("%d entries scheduled, want 5", got)
+	}
+	s.Remove("taken")
+	if s.Entry("taken") != nil {
+		t.Fatal("the removed entry is still scheduled")
+	}
+}
+
+func TestEntryLocation(t *testing.T) {
+	tehran, err := time.LoadLocation("Asia/Tehran")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	s := newScheduler(t, core.NewApp(), Location(tehran))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []EntryOption
+		location *time.Location
+	}{
+		{name: "scheduler location", location: tehran},
+		{name: "entry timezone", opts: []EntryOption{Timezone("UTC")}, location: time.UTC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := s.Cron("30 3 * * *", &recordJob{}, nil, append(tt.opts, Name(tt.name))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if next := entry.Next().In(tt.location); next.Hour() != 3 || next.Minute() != 30 {
+				t.Fatalf("next run at %v, want 03:30 in %v", next, tt.location)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	worker := &queueWorker{}
+	app := core.NewApp()
+	if err := app.Use(core.WorkerKeyword, func() (interface{}, error) { return worker, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newScheduler(t, app)
+	job := &recordJob{}
+
+	tests := []struct {
+		name       string
+		params     string
+		opts       []EntryOption
+		wantRuns   []string
+		wantQueued int
+	}{
+		{name: "inline", params: "inline", wantRuns: []string{"inline"}},
+		{name: "queued", params: "queued", opts: []EntryOption{Queued()}, wantRuns: []string{"inline"}, wantQueued: 1},
+		// A failing job is logged and does not stop the scheduler
+		{name: "panic", params: "panic", wantRuns: []string{"inline"}, wantQueued: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := s.Cron("@daily", job, tt.params, append(tt.opts, Name(tt.name))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s.run(entry)
+
+			if got := job.runs(); len(got) != len(tt.wantRuns) || len(got) > 0 && got[0] != tt.wantRuns[0] {
+				t.Fatalf("runs %v, want %v", got, tt.wantRuns)
+			}
+			if got := len(worker.params); got != tt.wantQueued {
+				t.Fatalf("%d jobs enqueued, want %d", got, tt.wantQueued)
+			}
+		})
+	}
+}
+
+func TestStartRunsEntries(t *testing.T) {
+	s := newScheduler(t, core.NewApp())
+	job := &recordJob{started: make(chan struct{}, 1)}
+	if _, err := s.Every(time.Second, job, "tick"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("a running scheduler started again")
+	}
+
+	select {
+	case <-job.started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("the entry did not run")
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s.Entry("record").Prev().IsZero() {
+		t.Fatal("the entry has no previous run")
+	}
+}
+
+func TestWithoutOverlapping(t *testing.T) {
+	locker := newLocker(t)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "without locker"},
+		{name: "with locker", opts: []Option{Locker(locker)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScheduler(t, core.NewApp(), tt.opts...)
+			if err := s.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			job := &recordJob{started: make(chan struct{}, 1), release: make(chan struct{})}
+			entry, err := s.Cron("@daily", job, "run", WithoutOverlapping(time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				s.run(entry)
+			}()
+			<-job.started
+
+			// The second run is skipped while the first one is running
+			s.run(entry)
+			close(job.release)
+			<-done
+
+			// Runs follow each other once the previous one finished
+			s.run(entry)
+			<-job.started
+
+			if got := len(job.runs()); got != 2 {
+				t.Fatalf("%d runs, want 2", got)
+			}
+		})
+	}
+}
+
+func TestWithoutOverlappingAcrossReplicas(t *testing.T) {
+	locker := newLocker(t)
+	s := newScheduler(t, core.NewApp(), Locker(locker))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	job := &recordJob{}
+	entry, err := s.Cron("@daily", job, "run", WithoutOverlapping(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another replica still runs the entry, e.g. it started before the lead changed
+	lock, err := locker.TryAcquire(context.Background(), DefaultLeaderKey+"_record", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.run(entry)
+	if got := len(job.runs()); got != 0 {
+		t.Fatalf("%d runs while another replica runs the entry, want 0", got)
+	}
+
+	if err := lock.Release(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.run(entry)
+	if got := len(job.runs()); got != 1 {
+		t.Fatalf("%d runs, want 1", got)
+	}
+}
+
+func TestLeaderElection(t *testing.T) {
+	ctx := context.Background()
+	locker := newLocker(t)
+	ttl := 60 * time.Millisecond
+
+	replicas := []*Scheduler{
+		newScheduler(t, core.NewApp(), Locker(locker), LeaderTTL(ttl)),
+		newScheduler(t, core.NewApp(), Locker(locker), LeaderTTL(ttl)),
+	}
+	jobs := []*recordJob{{}, {}}
+	entries := make([]*Entry, len(replicas))
+	for i, s := range replicas {
+		entry, err := s.Cron("@daily", jobs[i], "run")
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[i] = entry
+
+		if err := s.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first replica took the lead and keeps it by renewing it
+	time.Sleep(2 * ttl)
+	if !replicas[0].IsLeader() || replicas[1].IsLeader() {
+		t.Fatalf("leaders %v and %v, want only the first replica", replicas[0].IsLeader(), replicas[1].IsLeader())
+	}
+
+	for i, s := range replicas {
+		s.run(entries[i])
+	}
+	if len(jobs[0].runs()) != 1 || len(jobs[1].runs()) != 0 {
+		t.Fatalf("runs %v and %v, want only the leader to run", jobs[0].runs(), jobs[1].runs())
+	}
+
+	// The lead passes to the other replica once the leader shuts down
+	if err := replicas[0].Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !replicas[1].IsLeader() {
+		if time.Now().After(deadline) {
+			t.Fatal("the lead was not taken over")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := locker.TryAcquire(ctx, DefaultLeaderKey, ttl); !errors.Is(err, locks.ErrNotAcquired) {
+		t.Fatalf("TryAcquire of the leader key = %v, want ErrNotAcquired", err)
+	}
+}
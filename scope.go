@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// scopedInstance holds an instance created within a scope
+type scopedInstance struct {
+	key      Keywords
+	instance interface{}
+}
+
+// scopedEntry guards the creation of a scoped instance
+type scopedEntry struct {
+	mu       sync.Mutex
+	instance interface{}
+	created  bool
+}
+
+// Scope is a child container, typically bound to a request or a job.
+// Scoped services are created once per scope, transient services on every resolution,
+// and singleton services are resolved from the application.
+type Scope struct {
+	app     *App
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	entries map[Keywords]*scopedEntry
+	created []scopedInstance // Instances in creation order, dependencies first
+	closed  bool
+}
+
+// NewScope creates a scope bound to ctx. The scope ends when Close is called or ctx is done,
+// whichever happens first, and the instances created within it are stopped.
+func (a *App) NewScope(ctx context.Context) *Scope {
+	scopeCtx, cancel := context.WithCancel(ctx)
+
+	scope := &Scope{
+		app:     a,
+		ctx:     scopeCtx,
+		cancel:  cancel,
+		entries: make(map[Keywords]*scopedEntry),
+	}
+
+	context.AfterFunc(scopeCtx, func() {
+		scope.Close()
+	})
+
+	return scope
+}
+
+// App returns the application the scope belongs to.
+func (s *Scope) App() *App {
+	return s.app
+}
+
+// Context returns the context of the scope, which is cancelled when the scope ends.
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Get returns an instance for the given key according to its lifetime
+func (s *Scope) Get(key Keywords) (interface{}, error) {
+	lifetime, ok := s.app.singleton.Lifetime(string(key))
+	if !ok || lifetime == SingletonLifetime {
+		return s.app.Get(key)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("[Scope] cannot resolve %s: scope has ended", key)
+	}
+
+	if lifetime == TransientLifetime {
+		s.mu.Unlock()
+
+		instance, err := s.app.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		s.track(key, instance)
+		return instance, nil
+	}
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &scopedEntry{}
+		s.entries[key] = entry
+	}
+	s.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if !entry.created {
+		instance, err := s.app.singleton.createScoped(string(key), s)
+		if err != nil {
+			return nil, err
+		}
+		entry.instance = instance
+		entry.created = true
+		s.track(key, instance)
+	}
+
+	return entry.instance, nil
+}
+
+// Exists checks if a key is registered.
+func (s *Scope) Exists(key Keywords) bool {
+	return s.app.Exists(key)
+}
+
+// Close ends the scope and stops its instances in reverse creation order.
+func (s *Scope) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	created := s.created
+	s.created = nil
+	s.mu.Unlock()
+
+	defer s.cancel()
+
+	// The scope context may already be cancelled, so stop hooks run detached from it
+	ctx := context.WithoutCancel(s.ctx)
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		if err := s.app.runInstanceHooks(ctx, created[i].key, created[i].instance, true); err != nil {
+			errs = append(errs, fmt.Errorf("[Scope] failed to stop %s: %w", created[i].key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// track records an instance created within the scope
+func (s *Scope) track(key Keywords, instance interface{}) {
+	if instance == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.created = append(s.created, scopedInstance{key: key, instance: instance})
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// instance is a service counting its creations
+type instance struct {
+	id int64
+}
+
+func TestLifetimes(t *testing.T) {
+	app := NewApp()
+
+	var created atomic.Int64
+	newInstance := func() (interface{}, error) { return &instance{id: created.Add(1)}, nil }
+	if err := app.Use("singleton", newInstance); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.UseTransient("transient", newInstance); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.UseScoped("scoped", func(scope *Scope) (interface{}, error) { return newInstance() }); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := app.NewScope(context.Background()), app.NewScope(context.Background())
+	defer first.Close()
+	defer second.Close()
+
+	tests := []struct {
+		name     string
+		resolver Resolver
+		other    Resolver
+		key      Keywords
+		wantSame bool
+	}{
+		{name: "singleton across scopes", resolver: first, other: second, key: "singleton", wantSame: true},
+		{name: "singleton from the app and a scope", resolver: app, other: first, key: "singleton", wantSame: true},
+		{name: "transient from the app", resolver: app, other: app, key: "transient", wantSame: false},
+		{name: "transient within a scope", resolver: first, other: first, key: "transient", wantSame: false},
+		{name: "scoped within a scope", resolver: first, other: first, key: "scoped", wantSame: true},
+		{name: "scoped across scopes", resolver: first, other: second, key: "scoped", wantSame: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Resolve[*instance](tt.resolver, tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Resolve[*instance](tt.other, tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if same := a == b; same != tt.wantSame {
+				t.Fatalf("instances %d and %d, want the same instance: %v", a.id, b.id, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestScopedFromRoot(t *testing.T) {
+	app := NewApp()
+	if err := app.UseScoped("scoped", func(scope *Scope) (interface{}, error) { return &instance{}, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Get("scoped"); err == nil || !strings.Contains(err.Error(), "must be resolved from a scope") {
+		t.Fatalf("Get error = %v, want a scoped service error", err)
+	}
+	if _, err := Resolve[*instance](app, "scoped"); err == nil {
+		t.Fatal("Resolve from the app returned a scoped service")
+	}
+	if err := app.Replace(context.Background(), "scoped", func() (interface{}, error) { return &instance{}, nil }); err == nil {
+		t.Fatal("Replace of a scoped service succeeded")
+	}
+}
+
+func TestScopedCreatedOnceConcurrently(t *testing.T) {
+	app := NewApp()
+
+	var created atomic.Int64
+	if err := app.UseScoped("scoped", func(scope *Scope) (interface{}, error) {
+		created.Add(1)
+		time.Sleep(time.Millisecond)
+		return &instance{}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	scope := app.NewScope(context.Background())
+	defer scope.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := scope.Get("scoped"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := created.Load(); got != 1 {
+		t.Fatalf("%d instances created in the scope, want 1", got)
+	}
+}
+
+func TestScopeEndsWithContext(t *testing.T) {
+	app := NewApp()
+
+	var stops recorder
+	if err := app.Use("singleton", func() (interface{}, error) { return &instance{}, nil }, OnStop(stops.stop("singleton"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.UseTransient("transient", func() (interface{}, error) { return &instance{}, nil }, OnStop(stops.stop("transient"))); err != nil {
+		t.Fatal(err)
+	}
+	// The scoped service resolves the transient one, which is created first and stopped last
+	if err := app.UseScoped("scoped", func(scope *Scope) (interface{}, error) {
+		if _, err := scope.Get("transient"); err != nil {
+			return nil, err
+		}
+		return &instance{}, nil
+	}, OnStop(stops.stop("scoped"))); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scope := app.NewScope(ctx)
+	for _, key := range []Keywords{"singleton", "scoped"} {
+		if _, err := scope.Get(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cancel()
+	select {
+	case <-scope.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("the scope context was not cancelled")
+	}
+
+	// The scope is closed by context.AfterFunc, in its own goroutine
+	deadline := time.Now().Add(time.Second)
+	for stops.names() != "scoped,transient" {
+		if time.Now().After(deadline) {
+			t.Fatalf("stopped %q, want scoped,transient", stops.names())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := scope.Get("scoped"); err == nil {
+		t.Fatal("a scoped service was resolved from an ended scope")
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if got := stops.names(); got != "scoped,transient" {
+		t.Fatalf("stopped %q after the second Close, want scoped,transient", got)
+	}
+}
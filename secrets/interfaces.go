@@ -0,0 +1,9 @@
+package secrets
+
+// SecretsDriver returns the secrets referenced by the configuration, e.g. "secret://db/main/password".
+// Drivers implement config.SecretResolver.
+type SecretsDriver interface {
+	Name() string
+	Init(config interface{}) error
+	Secret(path string) (string, error)
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core/secrets/secrets_drivers"
+)
+
+type SecretsManager struct {
+	drivers map[string]SecretsDriver
+}
+
+func NewSecretsManager(drivers ...SecretsDriver) *SecretsManager {
+	manager := &SecretsManager{
+		drivers: make(map[string]SecretsDriver),
+	}
+
+	// register default driver
+	drivers = append(drivers, &secrets_drivers.EnvSecretsDriver{}, &secrets_drivers.FileSecretsDriver{}, &secrets_drivers.VaultSecretsDriver{})
+	manager.RegisterDrivers(drivers...)
+
+	return manager
+}
+
+func (sm *SecretsManager) RegisterDrivers(drivers ...SecretsDriver) {
+	for _, driver := range drivers {
+		sm.drivers[driver.Name()] = driver
+	}
+}
+
+func (sm *SecretsManager) CreateSecretsFactory(driverName string, config interface{}) (SecretsDriver, error) {
+	driver, exists := sm.drivers[driverName]
+	if !exists {
+		return nil, fmt.Errorf("unsupported secrets driver %s", driverName)
+	}
+
+	if err := driver.Init(config); err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
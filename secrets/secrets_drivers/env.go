@@ -0,0 +1,50 @@
+package secrets_drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/HemendCo/go-core/secrets/secrets_models"
+)
+
+// EnvSecretsDriver reads secrets from environment variables.
+type EnvSecretsDriver struct {
+	cfg *secrets_models.EnvSecretsConfig
+}
+
+func (e *EnvSecretsDriver) Name() string {
+	return "env"
+}
+
+func (e *EnvSecretsDriver) Init(config interface{}) error {
+	cfg, ok := config.(secrets_models.EnvSecretsConfig)
+	if !ok {
+		return errors.New("invalid env secrets configuration: expected a secrets_models.EnvSecretsConfig type")
+	}
+
+	if cfg.Prefix == "" {
+		cfg.Prefix = "SECRET"
+	}
+
+	e.cfg = &cfg
+	return nil
+}
+
+// Secret returns the value of the variable named after the path, "db/main/password" is read from SECRET_DB_MAIN_PASSWORD
+func (e *EnvSecretsDriver) Secret(path string) (string, error) {
+	name := e.variable(path)
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("secret %s not found: environment variable %s is not set", path, name)
+	}
+	return value, nil
+}
+
+// variable returns the environment variable name of a secret path
+func (e *EnvSecretsDriver) variable(path string) string {
+	name := strings.NewReplacer("/", "_", ".", "_", "-", "_", ":", "_").Replace(strings.Trim(path, "/"))
+	return strings.ToUpper(e.cfg.Prefix + "_" + name)
+}
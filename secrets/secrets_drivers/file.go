@@ -0,0 +1,55 @@
+package secrets_drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HemendCo/go-core/secrets/secrets_models"
+)
+
+// FileSecretsDriver reads every secret from its own file, like mounted Docker or Kubernetes secrets.
+type FileSecretsDriver struct {
+	cfg *secrets_models.FileSecretsConfig
+}
+
+func (f *FileSecretsDriver) Name() string {
+	return "file"
+}
+
+func (f *FileSecretsDriver) Init(config interface{}) error {
+	cfg, ok := config.(secrets_models.FileSecretsConfig)
+	if !ok {
+		return errors.New("invalid file secrets configuration: expected a secrets_models.FileSecretsConfig type")
+	}
+
+	f.cfg = &cfg
+	return nil
+}
+
+// Secret returns the content of the file at the path, without the trailing newline
+func (f *FileSecretsDriver) Secret(path string) (string, error) {
+	file, err := f.file(path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("secret %s not found: %w", path, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// file returns the file of a secret path, which must stay inside the secrets directory
+func (f *FileSecretsDriver) file(path string) (string, error) {
+	relative := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(path, "/")))
+	if relative == "." || !filepath.IsLocal(relative) {
+		return "", fmt.Errorf("invalid secret path %s", path)
+	}
+
+	return filepath.Join(f.cfg.Path, relative), nil
+}
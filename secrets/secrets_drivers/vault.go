@@ -0,0 +1,141 @@
+package secrets_drivers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/HemendCo/go-core/secrets/secrets_models"
+)
+
+// DefaultMasterKeyEnv is the environment variable the vault master key is read from by default
+const DefaultMasterKeyEnv = "SECRETS_MASTER_KEY"
+
+// VaultSecretsDriver reads secrets from a local file encrypted at rest with AES-256-GCM.
+// The file holds a JSON object of paths to secrets, sealed with SealVault.
+type VaultSecretsDriver struct {
+	mu      sync.RWMutex
+	secrets map[string]string
+}
+
+func (v *VaultSecretsDriver) Name() string {
+	return "vault"
+}
+
+// Init decrypts the vault file; calling it again reloads the file.
+func (v *VaultSecretsDriver) Init(config interface{}) error {
+	cfg, ok := config.(secrets_models.VaultSecretsConfig)
+	if !ok {
+		return errors.New("invalid vault secrets configuration: expected a secrets_models.VaultSecretsConfig type")
+	}
+
+	masterKey := cfg.MasterKey
+	if masterKey == "" {
+		env := cfg.MasterKeyEnv
+		if env == "" {
+			env = DefaultMasterKeyEnv
+		}
+		if masterKey = os.Getenv(env); masterKey == "" {
+			return fmt.Errorf("missing vault master key: set the %s environment variable", env)
+		}
+	}
+
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		return fmt.Errorf("error reading vault file '%s': %w", cfg.Path, err)
+	}
+
+	secrets, err := OpenVault(data, masterKey)
+	if err != nil {
+		return fmt.Errorf("error opening vault file '%s': %w", cfg.Path, err)
+	}
+
+	v.mu.Lock()
+	v.secrets = secrets
+	v.mu.Unlock()
+
+	return nil
+}
+
+func (v *VaultSecretsDriver) Secret(path string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	value, ok := v.secrets[strings.Trim(path, "/")]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found in vault", path)
+	}
+	return value, nil
+}
+
+// SealVault encrypts the secrets, keyed by path, into the content of a vault file.
+func SealVault(secrets map[string]string, masterKey string) ([]byte, error) {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := vaultCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// OpenVault decrypts the content of a vault file created by SealVault.
+func OpenVault(data []byte, masterKey string) (map[string]string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault encoding: %w", err)
+	}
+
+	gcm, err := vaultCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("invalid vault content")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt vault: wrong master key or corrupted file")
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid vault content: %w", err)
+	}
+	return secrets, nil
+}
+
+// vaultCipher derives the AES-256-GCM cipher of a master key
+func vaultCipher(masterKey string) (cipher.AEAD, error) {
+	if masterKey == "" {
+		return nil, errors.New("empty vault master key")
+	}
+
+	key := sha256.Sum256([]byte(masterKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
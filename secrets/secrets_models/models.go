@@ -0,0 +1,31 @@
+package secrets_models
+
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
+// EnvSecretsConfig reads the secret "db/main/password" from the variable <PREFIX>_DB_MAIN_PASSWORD.
+type EnvSecretsConfig struct {
+	Prefix string `mapstructure:"prefix"`
+}
+
+// FileSecretsConfig reads the secret "db/main/password" from the file <Path>/db/main/password.
+type FileSecretsConfig struct {
+	Path string `mapstructure:"path" validate:"required"`
+}
+
+// VaultSecretsConfig reads the secrets from a local file encrypted with a master key.
+// The master key is read from the MasterKeyEnv environment variable unless MasterKey is set.
+type VaultSecretsConfig struct {
+	Path         string `mapstructure:"path" validate:"required"`
+	MasterKey    string `mapstructure:"master_key"`
+	MasterKeyEnv string `mapstructure:"master_key_env"`
+}
+
+func (c VaultSecretsConfig) String() string {
+	type plain VaultSecretsConfig
+	c.MasterKey = helpers.Redact(c.MasterKey)
+	return fmt.Sprintf("%+v", plain(c))
+}
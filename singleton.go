@@ -3,61 +3,215 @@ package core
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
+// Lifetime defines how long a created instance is reused
+type Lifetime int
+
+const (
+	SingletonLifetime Lifetime = iota // One instance for the whole container
+	TransientLifetime                 // A new instance every time it is resolved
+	ScopedLifetime                    // One instance per scope
+)
+
+// RetryPolicy controls how a failed construction of a singleton instance is retried
+type RetryPolicy struct {
+	MaxAttempts    int           // Maximum number of failed attempts before giving up, 0 for unlimited
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Upper bound of the delay between retries
+	Multiplier     float64       // Factor applied to the delay after each failed attempt
+}
+
+// DefaultRetryPolicy retries failed constructions indefinitely with an exponential backoff
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    0,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// backoff returns the delay to wait after the given number of failed attempts
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempts && p.Multiplier > 1; i++ {
+		delay *= p.Multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(delay)
+}
+
 // instanceData struct to hold instance-related information
 type instanceData struct {
-	instance interface{} // Store the creator function as an interface{}
-	executed sync.Once   // Flag to ensure the function is executed only onceSingleton
+	mu         sync.Mutex
+	lifetime   Lifetime                                // How long the created instance is reused
+	createFunc func() (interface{}, error)             // Creator function of singleton and transient instances
+	scopedFunc func(scope *Scope) (interface{}, error) // Creator function of scoped instances
+	instance   interface{}                             // The created singleton instance
+	created    bool                                    // Whether the singleton instance has been created
+	err        error                                   // Error of the last failed construction
+	attempts   int                                     // Failed constructions since the last success
+	retryAt    time.Time                               // Earliest time of the next construction attempt
+}
+
+// reset drops the created instance and the construction error
+func (info *instanceData) reset() {
+	info.instance = nil
+	info.created = false
+	info.err = nil
+	info.attempts = 0
+	info.retryAt = time.Time{}
 }
 
 // Singleton struct for managing Singletons
 type Singleton struct {
 	instances sync.Map // Using sync.Map for concurrent access
+	policy    RetryPolicy
 }
 
 // NewSingleton creates a new Singleton factory
 func NewSingleton() *Singleton {
-	return &Singleton{}
+	return &Singleton{
+		policy: DefaultRetryPolicy(),
+	}
+}
+
+// SetRetryPolicy sets how failed constructions are retried
+func (s *Singleton) SetRetryPolicy(policy RetryPolicy) {
+	s.policy = policy
 }
 
 // Register a creator function for a given key
 func (s *Singleton) Register(key string, createFunc func() (interface{}, error)) error {
-	if _, ok := s.instances.Load(key); ok {
+	return s.store(key, &instanceData{createFunc: createFunc, lifetime: SingletonLifetime})
+}
+
+// RegisterTransient registers a creator function that is executed every time the key is resolved
+func (s *Singleton) RegisterTransient(key string, createFunc func() (interface{}, error)) error {
+	return s.store(key, &instanceData{createFunc: createFunc, lifetime: TransientLifetime})
+}
+
+// RegisterScoped registers a creator function that is executed once per scope
+func (s *Singleton) RegisterScoped(key string, createFunc func(scope *Scope) (interface{}, error)) error {
+	return s.store(key, &instanceData{scopedFunc: createFunc, lifetime: ScopedLifetime})
+}
+
+// store registers the instance data unless the key is already taken
+func (s *Singleton) store(key string, data *instanceData) error {
+	if _, loaded := s.instances.LoadOrStore(key, data); loaded {
 		return fmt.Errorf("[Singleton] instance for key %s already exists", key)
 	}
-
-	// Store the creator function as instanceData with executed flag set to sync.Once
-	s.instances.Store(key, &instanceData{instance: createFunc})
 	return nil
 }
 
-// Get returns an instance for the given key, creating it if it does not exist
-func (s *Singleton) Get(key string) (interface{}, error) {
+// Lifetime returns the lifetime of the instance registered for the given key
+func (s *Singleton) Lifetime(key string) (Lifetime, bool) {
 	if data, ok := s.instances.Load(key); ok {
-		info := data.(*instanceData) // Type assert to *instanceData
-
-		var newInstance interface{}
-		var err error
-
-		// Execute the creator function if it has not been executed yet
-		info.executed.Do(func() {
-			createFunc := info.instance.(func() (interface{}, error))
-			newInstance, err = createFunc() // Execute createFunc to create a new instance
-			if err == nil && newInstance != nil {
-				info.instance = newInstance // Update instance with the newly created instance
-			}
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("[Singleton] failed to create instance for key %s: %v", key, err)
-		}
+		return data.(*instanceData).lifetime, true
+	}
+	return SingletonLifetime, false
+}
+
+// Get returns an instance for the given key, creating it if it does not exist.
+// A failed construction is retried on a later call according to the retry policy;
+// until then the error of the failed attempt is returned.
+func (s *Singleton) Get(key string) (interface{}, error) {
+	data, ok := s.instances.Load(key)
+	if !ok {
+		return nil, fmt.Errorf("[Singleton] no instance found for key %s", key)
+	}
+
+	info := data.(*instanceData) // Type assert to *instanceData
+
+	switch info.lifetime {
+	case TransientLifetime:
+		return s.createTransient(key, info)
+	case ScopedLifetime:
+		return nil, fmt.Errorf("[Singleton] instance for key %s is scoped and must be resolved from a scope", key)
+	}
+
+	info.mu.Lock()
+	defer info.mu.Unlock()
 
-		// Return the instance (either created or previously stored)
+	// Return the instance if it was previously created
+	if info.created {
 		return info.instance, nil
 	}
 
-	return nil, fmt.Errorf("[Singleton] no instance found for key %s", key)
+	// Report the cached error while waiting for the next attempt or once attempts are exhausted
+	if info.err != nil {
+		exhausted := s.policy.MaxAttempts > 0 && info.attempts >= s.policy.MaxAttempts
+		if exhausted || time.Now().Before(info.retryAt) {
+			return nil, info.err
+		}
+	}
+
+	instance, err := info.createFunc() // Execute createFunc to create a new instance
+	if err != nil {
+		info.attempts++
+		info.err = fmt.Errorf("[Singleton] failed to create instance for key %s: %w", key, err)
+		info.retryAt = time.Now().Add(s.policy.backoff(info.attempts))
+		return nil, info.err
+	}
+
+	info.reset()
+	info.instance = instance
+	info.created = true
+
+	return info.instance, nil
+}
+
+// Instance returns the created singleton instance for the given key without creating it
+func (s *Singleton) Instance(key string) (interface{}, bool) {
+	data, ok := s.instances.Load(key)
+	if !ok {
+		return nil, false
+	}
+
+	info := data.(*instanceData)
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	return info.instance, info.created
+}
+
+// Reset drops the created instance and any cached error, so the next Get creates it again
+func (s *Singleton) Reset(key string) error {
+	data, ok := s.instances.Load(key)
+	if !ok {
+		return fmt.Errorf("[Singleton] no instance found for key %s", key)
+	}
+
+	info := data.(*instanceData)
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	info.reset()
+	return nil
+}
+
+// Replace swaps the creator function for the given key and drops the created instance
+func (s *Singleton) Replace(key string, createFunc func() (interface{}, error)) error {
+	data, ok := s.instances.Load(key)
+	if !ok {
+		return fmt.Errorf("[Singleton] no instance found for key %s", key)
+	}
+
+	info := data.(*instanceData)
+	if info.lifetime == ScopedLifetime {
+		return fmt.Errorf("[Singleton] instance for key %s is scoped and cannot be replaced", key)
+	}
+
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	info.createFunc = createFunc
+	info.reset()
+	return nil
 }
 
 // Exists checks if an instance exists for the given key
@@ -65,3 +219,30 @@ func (s *Singleton) Exists(key string) bool {
 	_, ok := s.instances.Load(key)
 	return ok
 }
+
+// createTransient executes the creator function of a transient instance
+func (s *Singleton) createTransient(key string, info *instanceData) (interface{}, error) {
+	info.mu.Lock()
+	createFunc := info.createFunc
+	info.mu.Unlock()
+
+	instance, err := createFunc()
+	if err != nil {
+		return nil, fmt.Errorf("[Singleton] failed to create instance for key %s: %w", key, err)
+	}
+	return instance, nil
+}
+
+// createScoped executes the creator function of a scoped instance for the given scope
+func (s *Singleton) createScoped(key string, scope *Scope) (interface{}, error) {
+	data, ok := s.instances.Load(key)
+	if !ok {
+		return nil, fmt.Errorf("[Singleton] no instance found for key %s", key)
+	}
+
+	instance, err := data.(*instanceData).scopedFunc(scope)
+	if err != nil {
+		return nil, fmt.Errorf("[Singleton] failed to create instance for key %s: %w", key, err)
+	}
+	return instance, nil
+}
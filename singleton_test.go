@@ -0,0 +1,152 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingletonGetRetriesFailedConstruction(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		failures     int64 // Failed constructions before the first success
+		wantInstance bool  // Whether Get eventually succeeds
+		maxAttempts  int64 // Upper bound of the constructions while the goroutines run
+	}{
+		{
+			name:         "succeeds after backoff",
+			policy:       RetryPolicy{InitialBackoff: 20 * time.Millisecond, Multiplier: 2},
+			failures:     2,
+			wantInstance: true,
+			maxAttempts:  3,
+		},
+		{
+			name:         "gives up after max attempts",
+			policy:       RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			failures:     10,
+			wantInstance: false,
+			maxAttempts:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSingleton()
+			s.SetRetryPolicy(tt.policy)
+
+			var attempts atomic.Int64
+			if err := s.Register("service", func() (interface{}, error) {
+				if attempts.Add(1) <= tt.failures {
+					return nil, errors.New("unavailable")
+				}
+				return new(int), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				wg        sync.WaitGroup
+				mu        sync.Mutex
+				instances = make(map[interface{}]bool)
+			)
+			deadline := time.Now().Add(200 * time.Millisecond)
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for time.Now().Before(deadline) {
+						instance, err := s.Get("service")
+						if err == nil {
+							mu.Lock()
+							instances[instance] = true
+							mu.Unlock()
+						}
+						time.Sleep(time.Millisecond)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := attempts.Load(); got > tt.maxAttempts {
+				t.Errorf("constructions = %d, want at most %d: the backoff was not respected", got, tt.maxAttempts)
+			}
+			if tt.wantInstance && len(instances) != 1 {
+				t.Errorf("distinct instances = %d, want 1", len(instances))
+			}
+			if !tt.wantInstance && len(instances) != 0 {
+				t.Errorf("got an instance after the attempts were exhausted")
+			}
+		})
+	}
+}
+
+func TestSingletonResetAndReplaceRaceWithGet(t *testing.T) {
+	s := NewSingleton()
+	s.SetRetryPolicy(RetryPolicy{})
+
+	if err := s.Register("service", func() (interface{}, error) {
+		return "original", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	errs := make(chan error, 1)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				instance, err := s.Get("service")
+				if err == nil && instance != "original" && instance != "replaced" {
+					err = errors.New("unexpected instance")
+				}
+				if err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		if i%2 == 0 {
+			if err := s.Reset("service"); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			if err := s.Replace("service", func() (interface{}, error) {
+				return "replaced", nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+
+	instance, err := s.Get("service")
+	if err != nil || instance != "replaced" {
+		t.Fatalf("Get after Replace = %v, %v; want replaced", instance, err)
+	}
+}
@@ -11,3 +11,8 @@ type SMSDriver interface {
 	Init(app *core.App, config interface{}) error
 	SendMessage(mobileNumber string, message string, sendDateTime *time.Time) (*sms_models.SMSResponse, error)
 }
+
+// ReloadableDriver is implemented by drivers that can be re-initialized with a new configuration.
+type ReloadableDriver interface {
+	Reload(config interface{}) error
+}
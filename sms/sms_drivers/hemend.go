@@ -2,14 +2,17 @@ package sms_drivers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/HemendCo/go-core"
 	"github.com/HemendCo/go-core/cache"
+	"github.com/HemendCo/go-core/locks"
 	"github.com/HemendCo/go-core/sms/sms_models"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -22,7 +25,8 @@ type HemendSMSResponse struct {
 type HemendSMSDriver struct {
 	app   *core.App
 	cfg   *sms_models.HemendSMSConfig
-	cache cache.CacheDriver
+	cache *cache.Repository
+	mu    sync.RWMutex
 }
 
 // Name implements the name for the driver
@@ -30,41 +34,79 @@ func (hs *HemendSMSDriver) Name() string {
 	return "hemend"
 }
 
-// Init creates a new HemendSMSDriver and configures it with settings
+// Init creates a new HemendSMSDriver and configures it with settings.
+// Calling Init again with a different configuration re-initializes the driver.
 func (hs *HemendSMSDriver) Init(app *core.App, config interface{}) error {
-	if hs.app != nil {
+	cfg, ok := config.(sms_models.HemendSMSConfig)
+	if !ok {
+		return errors.New("invalid hemend sms configuration: expected a sms_models.HemendSMSConfig type")
+	}
+
+	hs.mu.RLock()
+	initialized := hs.app != nil
+	unchanged := initialized && *hs.cfg == cfg
+	hs.mu.RUnlock()
+
+	if unchanged {
 		return nil
 	}
 
-	cfg, ok := config.(sms_models.HemendSMSConfig)
-	if !ok {
-		return errors.New("invalid file logger configuration: expected a logger_models.FileLoggerConfig type")
+	if initialized {
+		return hs.Reload(cfg)
 	}
 
-	// Attempt to get the service from the app
-	service, err := app.Get(core.CacheKeyword)
+	// Attempt to get the cache service from the app
+	driver, err := core.Resolve[cache.CacheDriver](app, core.CacheKeyword)
 	if err != nil {
 		return fmt.Errorf("failed to get cache service: %w", err)
 	}
 
-	// Attempt to assert the serviceValue to type T
-	cache, ok := service.(cache.CacheDriver)
-	if !ok {
-		return fmt.Errorf("unsupported cache service: expected type .CacheDriver but got %T", service)
+	repository, err := cache.NewRepository(driver)
+	if err != nil {
+		return err
 	}
 
+	hs.mu.Lock()
 	hs.app = app
 	hs.cfg = &cfg
-	hs.cache = cache
+	hs.cache = repository
+	hs.mu.Unlock()
 
 	return nil
 }
 
+// Reload applies a new configuration; the cached token is dropped when the credentials change
+func (hs *HemendSMSDriver) Reload(config interface{}) error {
+	cfg, ok := config.(sms_models.HemendSMSConfig)
+	if !ok {
+		return errors.New("invalid hemend sms configuration: expected a sms_models.HemendSMSConfig type")
+	}
+
+	hs.mu.Lock()
+	previous := hs.cfg
+	hs.cfg = &cfg
+	hs.mu.Unlock()
+
+	if previous != nil && (previous.ApiKey != cfg.ApiKey || previous.SecretKey != cfg.SecretKey || previous.IsTest != cfg.IsTest) {
+		return hs.cache.Delete(hs.app.GetContext(), hs.cacheTokenKey(previous))
+	}
+
+	return nil
+}
+
+// config returns the current configuration
+func (hs *HemendSMSDriver) config() *sms_models.HemendSMSConfig {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	return hs.cfg
+}
+
 // SendMessage sends an SMS message
 func (hs *HemendSMSDriver) SendMessage(mobileNumber string, message string, sendDateTime *time.Time) (*sms_models.SMSResponse, error) {
 	res := sms_models.SMSResponse{}
 
-	token, err := hs.getToken()
+	token, err := hs.getToken(hs.app.GetContext())
 	if err != nil {
 		res.StatusCode = sms_models.TokenExpiredStatusCode
 		return &res, nil
@@ -77,7 +119,7 @@ func (hs *HemendSMSDriver) SendMessage(mobileNumber string, message string, send
 	}
 
 	url := hs.getAPIMessageSendUrl() + "/message.send"
-	hemendResponse, err := hs.execute(postData, url, &token)
+	hemendResponse, err := hs.execute(hs.app.GetContext(), postData, url, &token)
 	if err != nil {
 		res.StatusCode = sms_models.InternalServerErrorStatusCode
 		return &res, nil
@@ -105,58 +147,100 @@ func (hs *HemendSMSDriver) SendMessage(mobileNumber string, message string, send
 	return &res, nil
 }
 
+// Health checks that an access token can be obtained, from the cache or the token endpoint
+func (hs *HemendSMSDriver) Health(ctx context.Context) error {
+	_, err := hs.getToken(ctx)
+	return err
+}
+
 // getAPIMessageSendUrl gets the API URL for sending messages
 func (hs *HemendSMSDriver) getAPIMessageSendUrl() string {
+	cfg := hs.config()
 	baseURL := "https://sms.hemend.com/api/"
-	if hs.cfg.IsTest {
-		return baseURL + "test/" + hs.cfg.Version
+	if cfg.IsTest {
+		return baseURL + "test/" + cfg.Version
 	}
-	return baseURL + "main/" + hs.cfg.Version
+	return baseURL + "main/" + cfg.Version
 }
 
-// getToken retrieves the access token from cache or requests a new one
-func (hs *HemendSMSDriver) getToken() (string, error) {
-	cacheKey := hs.getCacheTokenKey()
-	token, err := hs.cache.Get(cacheKey) // Retrieve token from cache
+// tokenLockTTL bounds how long a replica holds the token refresh lock
+const tokenLockTTL = 30 * time.Second
+
+// getToken retrieves the access token from cache or requests a new one.
+// When a locks service is registered, only one replica requests a new token at a time.
+func (hs *HemendSMSDriver) getToken(ctx context.Context) (string, error) {
+	cfg := hs.config()
+	cacheKey := hs.cacheTokenKey(cfg)
+	token, found, err := cache.GetAs[string](ctx, hs.cache, cacheKey) // Retrieve token from cache
 
 	if err != nil {
 		return "", errors.New("invalid token type in cache")
 	}
 
-	if token == nil {
-		// Token not in cache, request a new one
-		postData := map[string]string{
-			"api_key":    hs.cfg.ApiKey,
-			"secret_key": hs.cfg.SecretKey,
+	if !found {
+		if !hs.app.Exists(core.LocksKeyword) {
+			return hs.requestToken(ctx, cfg, cacheKey)
 		}
 
-		url := hs.getAPIMessageSendUrl() + "/auth.getToken"
-		tokenRes, err := hs.execute(postData, url, nil)
+		locker, err := core.Resolve[*locks.Locker](hs.app, core.LocksKeyword)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("failed to get locks service: %w", err)
 		}
 
-		// Validate the response
-		if tokenRes.(map[string]interface{})["status_code"] == "OK" {
-			token = tokenRes.(map[string]interface{})["token"].(map[string]interface{})["access_token"].(string)
-			expiresIn := tokenRes.(map[string]interface{})["token"].(map[string]interface{})["expires_in"].(string)
-
-			location, err := time.LoadLocation(hs.cfg.Timezone)
-			if err != nil {
-				return "", err
+		var strToken string
+		err = locker.WithLock(ctx, cacheKey, tokenLockTTL, func(ctx context.Context) error {
+			// Another replica may have refreshed the token while we waited for the lock
+			if token, found, err := cache.GetAs[string](ctx, hs.cache, cacheKey); err == nil && found {
+				strToken = token
+				return nil
 			}
 
-			// Parse the expiration time string to time.Time
-			parsedTime, err := time.ParseInLocation(time.RFC3339, expiresIn, location)
-			if err != nil {
-				return "", err
-			}
+			strToken, err = hs.requestToken(ctx, cfg, cacheKey)
+			return err
+		})
+		return strToken, err
+	}
+
+	return token, nil
+}
+
+// requestToken requests a new access token and caches it until shortly before it expires
+func (hs *HemendSMSDriver) requestToken(ctx context.Context, cfg *sms_models.HemendSMSConfig, cacheKey string) (string, error) {
+	var token interface{}
+
+	postData := map[string]string{
+		"api_key":    cfg.ApiKey,
+		"secret_key": cfg.SecretKey,
+	}
+
+	url := hs.getAPIMessageSendUrl() + "/auth.getToken"
+	tokenRes, err := hs.execute(ctx, postData, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Validate the response
+	if tokenRes.(map[string]interface{})["status_code"] == "OK" {
+		token = tokenRes.(map[string]interface{})["token"].(map[string]interface{})["access_token"].(string)
+		expiresIn := tokenRes.(map[string]interface{})["token"].(map[string]interface{})["expires_in"].(string)
+
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return "", err
+		}
+
+		// Parse the expiration time string to time.Time
+		parsedTime, err := time.ParseInLocation(time.RFC3339, expiresIn, location)
+		if err != nil {
+			return "", err
+		}
 
-			// Calculate expiration time for cache, subtracting 60 seconds
-			expireTime := parsedTime.Add(-60 * time.Second)
+		// Calculate expiration time for cache, subtracting 60 seconds
+		expireTime := parsedTime.Add(-60 * time.Second)
 
-			// Use time.Until to set the cache expiration duration
-			if err := hs.cache.Set(cacheKey, token, time.Until(expireTime)); err != nil {
+		// Use time.Until to set the cache expiration duration; a token about to expire is not cached
+		if ttl := time.Until(expireTime); ttl > 0 {
+			if err := hs.cache.Set(ctx, cacheKey, token, ttl); err != nil {
 				return "", err
 			}
 		}
@@ -171,13 +255,13 @@ func (hs *HemendSMSDriver) getToken() (string, error) {
 }
 
 // execute sends an HTTP request to the specified URL with the given postData
-func (hs *HemendSMSDriver) execute(postData interface{}, url string, token *string) (interface{}, error) {
+func (hs *HemendSMSDriver) execute(ctx context.Context, postData interface{}, url string, token *string) (interface{}, error) {
 	postString, err := json.Marshal(postData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(postString))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(postString))
 	if err != nil {
 		return nil, err
 	}
@@ -211,9 +295,9 @@ func (hs *HemendSMSDriver) execute(postData interface{}, url string, token *stri
 	return result, nil
 }
 
-// getCacheTokenKey returns the cache key for the token
-func (hs *HemendSMSDriver) getCacheTokenKey() string {
-	if hs.cfg.IsTest {
+// cacheTokenKey returns the cache key for the token of the given configuration
+func (hs *HemendSMSDriver) cacheTokenKey(cfg *sms_models.HemendSMSConfig) string {
+	if cfg.IsTest {
 		return "hemend_sms_token_test"
 	}
 	return "hemend_sms_token"
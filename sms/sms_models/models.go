@@ -1,5 +1,11 @@
 package sms_models
 
+import (
+	"fmt"
+
+	"github.com/HemendCo/go-core/helpers"
+)
+
 type StatusCode int
 
 const (
@@ -32,9 +38,17 @@ type SMSResponse struct {
 }
 
 type HemendSMSConfig struct {
-	ApiKey    string
-	SecretKey string
-	Version   string
-	IsTest    bool
-	Timezone  string
+	ApiKey    string `mapstructure:"api_key" validate:"required"`
+	SecretKey string `mapstructure:"secret_key" validate:"required"`
+	Version   string `mapstructure:"version"`
+	IsTest    bool   `mapstructure:"is_test"`
+	Timezone  string `mapstructure:"timezone"`
+}
+
+// String formats the configuration with the credentials redacted
+func (c HemendSMSConfig) String() string {
+	type plain HemendSMSConfig
+	c.ApiKey = helpers.Redact(c.ApiKey)
+	c.SecretKey = helpers.Redact(c.SecretKey)
+	return fmt.Sprintf("%+v", plain(c))
 }
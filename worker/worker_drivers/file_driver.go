@@ -1,6 +1,7 @@
 package worker_drivers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/HemendCo/go-core"
@@ -26,6 +27,10 @@ type FileWorkerDriver struct {
 	fileManager *filemanager.FileManager // FileManager for file operations
 	jobs        []worker_interfaces.Job  // Registered job handlers
 	location    *time.Location
+	mu          sync.Mutex
+	done        chan struct{} // Closed when the driver is asked to stop
+	stopped     chan struct{} // Closed when Run has returned
+	closeOnce   sync.Once
 }
 
 // Name returns the name of the worker driver.
@@ -51,9 +56,20 @@ func (f *FileWorkerDriver) Init(app *core.App, config interface{}) error {
 	f.path = helpers.JoinWithProjectPath(f.cfg.Path)
 	f.location = location
 
+	f.mu.Lock()
+	if f.done == nil {
+		f.done = make(chan struct{})
+	}
+	f.mu.Unlock()
+
 	return os.MkdirAll(f.path, os.ModePerm) // Ensure the file exists
 }
 
+// Health checks that the task directory is writable.
+func (f *FileWorkerDriver) Health(ctx context.Context) error {
+	return helpers.CheckWritableDir(f.path)
+}
+
 // Enqueue adds a new job to the file queue.
 func (f *FileWorkerDriver) Enqueue(job worker_interfaces.Job, params interface{}) error {
 	preJson, err := job.NewTask(f.app, params)
@@ -117,16 +133,40 @@ func (f *FileWorkerDriver) JobHandlerExists(handler worker_interfaces.Job) bool
 	return false
 }
 
-// Close performs any necessary cleanup for the FileWorkerDriver.
+// Close stops the processing loop and waits for the tasks in progress to finish.
 func (f *FileWorkerDriver) Close() error {
-	// Any cleanup logic can be added here if needed
+	f.mu.Lock()
+	done, stopped := f.done, f.stopped
+	f.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	f.closeOnce.Do(func() {
+		close(done)
+	})
+
+	// Wait for Run to drain the current batch of tasks
+	if stopped != nil {
+		<-stopped
+	}
+
 	return nil
 }
 
-// Run starts processing the tasks with registered job handlers.
+// Run starts processing the tasks with registered job handlers until Close is called
+// or the application context is cancelled.
 func (f *FileWorkerDriver) Run(handlers ...worker_interfaces.Job) error {
 	f.RegisterJobHandlers(handlers...)
 
+	f.mu.Lock()
+	done := f.done
+	stopped := make(chan struct{})
+	f.stopped = stopped
+	f.mu.Unlock()
+	defer close(stopped)
+
 	timeAndUUIDPattern := `^([0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}-[0-9]{2}-[0-9]{2})_([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`
 	regex, err := regexp.Compile(timeAndUUIDPattern)
 	if err != nil {
@@ -143,8 +183,14 @@ func (f *FileWorkerDriver) Run(handlers ...worker_interfaces.Job) error {
 		if err := f.processFiles(regex, regex2); err != nil {
 			fmt.Printf("Error processing files: %v. Please wait a moment while we attempt the next check...\n", err)
 		}
-		if f.cfg.CheckInterval > 0 {
-			time.Sleep(time.Duration(f.cfg.CheckInterval) * time.Second) // Sleep before next check
+
+		// Sleep before next check unless the driver is closed
+		select {
+		case <-done:
+			return nil
+		case <-f.app.GetContext().Done():
+			return nil
+		case <-time.After(time.Duration(f.cfg.CheckInterval) * time.Second):
 		}
 	}
 }
@@ -0,0 +1,42 @@
+package worker_drivers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HemendCo/go-core"
+	"github.com/HemendCo/go-core/worker/worker_models"
+)
+
+func TestFileWorkerStopsWithApplication(t *testing.T) {
+	wd, _ := os.Getwd()
+	path, err := filepath.Rel(wd, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := core.NewApp()
+	driver := &FileWorkerDriver{}
+	if err := driver.Init(app, worker_models.FileWorkerConfig{Path: path, CheckInterval: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- driver.Run() }()
+
+	// Run waits a minute between checks, unless the root context of the application is cancelled
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return once the application was shut down")
+	}
+}
@@ -7,16 +7,24 @@ import (
 	"github.com/HemendCo/go-core"
 	"github.com/HemendCo/go-core/worker/worker_interfaces"
 	"github.com/HemendCo/go-core/worker/worker_models"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/hibiken/asynq"
 )
 
 type RedisWorkerDriver struct {
-	app    *core.App
-	cfg    worker_models.RedisWorkerConfig
-	client *asynq.Client
-	server *asynq.Server
-	jobs   []worker_interfaces.Job
+	app       *core.App
+	cfg       worker_models.RedisWorkerConfig
+	client    *asynq.Client
+	server    *asynq.Server
+	jobs      []worker_interfaces.Job
+	mu        sync.Mutex
+	done      chan struct{} // Closed when the driver is asked to stop
+	stopped   chan struct{} // Closed when Run has returned
+	closeOnce sync.Once
 }
 
 func (r *RedisWorkerDriver) Name() string {
@@ -32,6 +40,12 @@ func (r *RedisWorkerDriver) Init(app *core.App, config interface{}) error {
 	r.app = app
 	r.cfg = cfg
 
+	r.mu.Lock()
+	if r.done == nil {
+		r.done = make(chan struct{})
+	}
+	r.mu.Unlock()
+
 	return nil
 }
 
@@ -52,7 +66,7 @@ func (r *RedisWorkerDriver) Enqueue(job worker_interfaces.Job, params interface{
 	queue := "default"
 
 	// Enqueue the task to the Redis queue
-	_, err = r.getClient().Enqueue(asynqTask, asynq.Queue(queue), asynq.MaxRetry(r.cfg.MaxRetry))
+	_, err = r.getClient().EnqueueContext(r.app.GetContext(), asynqTask, asynq.Queue(queue), asynq.MaxRetry(r.cfg.MaxRetry))
 	return err
 }
 
@@ -84,21 +98,84 @@ func (r *RedisWorkerDriver) JobHandlerExists(handler worker_interfaces.Job) bool
 	return false
 }
 
+// Run processes tasks until Close is called, the application context is cancelled
+// or SIGINT/SIGTERM is received, then waits for the active tasks to finish.
 func (r *RedisWorkerDriver) Run(handlers ...worker_interfaces.Job) error {
 	r.RegisterJobHandlers(handlers...)
 
-	if err := r.getServer().Run(asynq.HandlerFunc(r.jobHandler())); err != nil {
+	r.mu.Lock()
+	done := r.done
+	stopped := make(chan struct{})
+	r.stopped = stopped
+	r.mu.Unlock()
+	defer close(stopped)
+
+	server := r.getServer()
+	if err := server.Start(asynq.HandlerFunc(r.jobHandler())); err != nil {
 		return err
 	}
 
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case <-signals:
+	case <-done:
+	case <-r.app.GetContext().Done():
+	}
+
+	server.Shutdown()
 	return nil
 }
 
+// Close stops the server, waits for the active tasks to finish and closes the client.
 func (r *RedisWorkerDriver) Close() error {
-	return r.client.Close()
+	r.mu.Lock()
+	done, stopped := r.done, r.stopped
+	r.mu.Unlock()
+
+	if done != nil {
+		r.closeOnce.Do(func() {
+			close(done)
+		})
+	}
+
+	// Wait for Run to drain the active tasks
+	if stopped != nil {
+		<-stopped
+	}
+
+	r.mu.Lock()
+	client := r.client
+	r.mu.Unlock()
+
+	if client != nil {
+		return client.Close()
+	}
+
+	return nil
+}
+
+// Health pings the Redis server.
+func (r *RedisWorkerDriver) Health(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- r.getClient().Ping()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (r *RedisWorkerDriver) getClient() *asynq.Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.client != nil {
 		return r.client
 	}
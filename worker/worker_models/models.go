@@ -1,6 +1,11 @@
 package worker_models
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/HemendCo/go-core/helpers"
+)
 
 type TaskMessage struct {
 	ID        string
@@ -30,22 +35,29 @@ func (q *TaskMessage) SetPayload(payload []byte) {
 }
 
 type RedisWorkerConfig struct {
-	Host        string
-	Port        string
-	Username    string
-	Password    string
-	Database    int
-	MaxRetry    int
-	Concurrency int
-	Priorities  map[string]int
+	Host        string         `mapstructure:"host" validate:"required"`
+	Port        string         `mapstructure:"port"`
+	Username    string         `mapstructure:"username"`
+	Password    string         `mapstructure:"password"`
+	Database    int            `mapstructure:"database"`
+	MaxRetry    int            `mapstructure:"max_retry"`
+	Concurrency int            `mapstructure:"concurrency"`
+	Priorities  map[string]int `mapstructure:"priorities"`
 }
 
 type FileWorkerConfig struct {
-	Path              string
-	MaxRetry          int
-	CheckInterval     int
-	TaskSleepDuration float64
-	Concurrency       int
-	Priorities        map[string]int
-	Timezone          string
+	Path              string         `mapstructure:"path" validate:"required"`
+	MaxRetry          int            `mapstructure:"max_retry"`
+	CheckInterval     int            `mapstructure:"check_interval"`
+	TaskSleepDuration float64        `mapstructure:"task_sleep_duration"`
+	Concurrency       int            `mapstructure:"concurrency"`
+	Priorities        map[string]int `mapstructure:"priorities"`
+	Timezone          string         `mapstructure:"timezone"`
+}
+
+// String formats the configuration with the credentials redacted
+func (c RedisWorkerConfig) String() string {
+	type plain RedisWorkerConfig
+	c.Password = helpers.Redact(c.Password)
+	return fmt.Sprintf("%+v", plain(c))
 }